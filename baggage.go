@@ -0,0 +1,208 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Limits of a W3C baggage header that every platform must be able to propagate.
+// See https://www.w3.org/TR/baggage/#limits.
+const (
+	// MaxBaggageMembers is the maximum number of list-members EncodeBaggage produces.
+	MaxBaggageMembers = 64
+	// MaxBaggageBytes is the maximum length in bytes of the baggage-string EncodeBaggage produces.
+	MaxBaggageBytes = 8192
+)
+
+var (
+	// ErrInvalidBaggageKey is returned when a key name is not a valid baggage key,
+	// which must be an HTTP token. In particular, anonymous keys are rejected since their
+	// names include call-site paths.
+	ErrInvalidBaggageKey = errors.New("invalid baggage key")
+
+	// ErrBaggageTooLarge is returned when the encoded baggage exceeds MaxBaggageMembers
+	// or MaxBaggageBytes.
+	ErrBaggageTooLarge = errors.New("baggage too large")
+
+	// ErrMalformedBaggage is returned when a baggage list-member cannot be parsed.
+	ErrMalformedBaggage = errors.New("malformed baggage")
+)
+
+// EncodeBaggage encodes the propagatable keys set in the context into W3C baggage
+// list-members such as "new-ui=true,max-items=100", so that feature values ride along with
+// tracing context. See https://www.w3.org/TR/baggage/.
+//
+// Only keys created with WithPropagation are encoded. Key names are used as baggage keys and
+// must be HTTP tokens; values are percent-encoded as required by the specification.
+// It returns ErrInvalidBaggageKey for anonymous or otherwise invalid names, and
+// ErrBaggageTooLarge if the result would exceed MaxBaggageMembers or MaxBaggageBytes.
+//
+// The result can be appended to an existing baggage header with a comma separator;
+// keep the combined header within the same limits.
+func (r *Registry) EncodeBaggage(ctx context.Context) (string, error) {
+	for _, entry := range r.snapshot() {
+		if !entry.Propagated || !entry.Anonymous {
+			continue
+		}
+
+		// Only values set in the context are propagated, so look them up without calling hooks
+		// or consulting overrides.
+		if _, set, _ := entry.Key.formatAny(ctx); set {
+			return "", fmt.Errorf("%w: anonymous key %s", ErrInvalidBaggageKey, entry.Name)
+		}
+	}
+
+	values, err := r.encodePropagated(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if len(values) > MaxBaggageMembers {
+		return "", fmt.Errorf("%w: %d list-members exceed the limit of %d",
+			ErrBaggageTooLarge, len(values), MaxBaggageMembers)
+	}
+
+	var b strings.Builder
+
+	for idx, v := range values {
+		if !isToken(v.name) {
+			return "", fmt.Errorf("%w: %q is not a token", ErrInvalidBaggageKey, v.name)
+		}
+
+		if idx > 0 {
+			b.WriteByte(',')
+		}
+
+		b.WriteString(v.name)
+		b.WriteByte('=')
+		b.WriteString(escapeBaggageValue(v.text))
+	}
+
+	if b.Len() > MaxBaggageBytes {
+		return "", fmt.Errorf("%w: %d bytes exceed the limit of %d", ErrBaggageTooLarge, b.Len(), MaxBaggageBytes)
+	}
+
+	return b.String(), nil
+}
+
+// DecodeBaggage returns a new context with the values decoded from a W3C baggage header.
+//
+// List-members whose key is not the name of a propagatable key recorded in the Registry are
+// ignored, since baggage is shared with other systems. Properties are ignored as well.
+// If some list-members cannot be decoded, DecodeBaggage still returns a context containing the
+// values that could, together with an error joining an ErrMalformedBaggage, *ParseError or
+// *ValidationError for each offending list-member.
+func (r *Registry) DecodeBaggage(ctx context.Context, baggage string) (context.Context, error) {
+	var (
+		bindings []Binding
+		errs     []error
+	)
+
+	for _, member := range strings.Split(baggage, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+
+		binding, ok, err := r.decodeBaggageMember(member)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		if ok {
+			bindings = append(bindings, binding)
+		}
+	}
+
+	return withBindings(ctx, bindings), errors.Join(errs...)
+}
+
+// decodeBaggageMember decodes a single "<key>=<value>;<properties>" list-member.
+// It returns false if the list-member does not belong to a propagatable key.
+func (r *Registry) decodeBaggageMember(member string) (Binding, bool, error) {
+	var zero Binding
+
+	member, _, _ = strings.Cut(member, ";")
+
+	rawKey, rawValue, ok := strings.Cut(member, "=")
+	if !ok {
+		return zero, false, fmt.Errorf("%w: missing '=' in %q", ErrMalformedBaggage, member)
+	}
+
+	name := strings.TrimSpace(rawKey)
+	if !isToken(name) {
+		return zero, false, fmt.Errorf("%w: %q is not a token", ErrMalformedBaggage, name)
+	}
+
+	if entry, ok := r.lookupNamed(name); !ok || !entry.Propagated {
+		return zero, false, nil
+	}
+
+	text, err := url.PathUnescape(strings.TrimSpace(rawValue))
+	if err != nil {
+		return zero, false, fmt.Errorf("%w: %w", ErrMalformedBaggage, err)
+	}
+
+	binding, err := r.decodePropagated(name, text)
+
+	return binding, err == nil, err
+}
+
+// escapeBaggageValue percent-encodes every byte that is not a baggage-octet, as well as
+// the percent sign itself.
+func escapeBaggageValue(value string) string {
+	const hex = "0123456789ABCDEF"
+
+	var b strings.Builder
+
+	for idx := 0; idx < len(value); idx++ {
+		c := value[idx]
+		if isBaggageOctet(c) && c != '%' {
+			b.WriteByte(c)
+
+			continue
+		}
+
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&0x0f])
+	}
+
+	return b.String()
+}
+
+// isBaggageOctet reports whether c is a baggage-octet:
+// printable US-ASCII excluding space, DQUOTE, comma, semicolon and backslash.
+func isBaggageOctet(c byte) bool {
+	return c > ' ' && c < 0x7f && c != '"' && c != ',' && c != ';' && c != '\\'
+}
+
+// isToken reports whether s is an HTTP token as defined in RFC 9110.
+func isToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for idx := 0; idx < len(s); idx++ {
+		if !isTokenChar(s[idx]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isTokenChar reports whether c is a tchar as defined in RFC 9110.
+func isTokenChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	default:
+		return strings.IndexByte("!#$%&'*+-.^_`|~", c) >= 0
+	}
+}
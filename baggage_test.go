@@ -0,0 +1,202 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestBaggage tests encoding and decoding feature values as W3C baggage.
+func TestBaggage(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip of propagatable keys", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		greeting := feature.NewNamed[string]("greeting", feature.WithRegistry(registry), feature.WithPropagation())
+		internal := feature.NewNamedBool("internal", feature.WithRegistry(registry))
+
+		ctx := context.Background()
+		ctx = newUI.WithEnabled(ctx)
+		ctx = greeting.WithValue(ctx, `hello, "world"; 100%`)
+		ctx = internal.WithEnabled(ctx)
+
+		baggage, err := registry.EncodeBaggage(ctx)
+		if err != nil {
+			t.Fatalf("EncodeBaggage() error = %v", err)
+		}
+
+		want := "new-ui=true,greeting=hello%2C%20%22world%22%3B%20100%25"
+		if baggage != want {
+			t.Errorf("EncodeBaggage() = %q, want %q", baggage, want)
+		}
+
+		decoded, err := registry.DecodeBaggage(context.Background(), baggage)
+		if err != nil {
+			t.Fatalf("DecodeBaggage() error = %v", err)
+		}
+
+		if !newUI.Enabled(decoded) {
+			t.Error("newUI.Enabled() = false, want true")
+		}
+
+		if got := greeting.Get(decoded); got != `hello, "world"; 100%` {
+			t.Errorf("greeting.Get() = %q, want %q", got, `hello, "world"; 100%`)
+		}
+
+		if internal.IsSet(decoded) {
+			t.Error("internal.IsSet() = true, want false")
+		}
+	})
+
+	t.Run("foreign members and properties are ignored", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+		internal := feature.NewNamedBool("internal", feature.WithRegistry(registry))
+
+		ctx, err := registry.DecodeBaggage(context.Background(),
+			"userId=alice, max-items = 100 ;ttl=30 , internal=true,,")
+		if err != nil {
+			t.Fatalf("DecodeBaggage() error = %v", err)
+		}
+
+		if got := maxItems.Get(ctx); got != 100 {
+			t.Errorf("maxItems.Get() = %d, want 100", got)
+		}
+
+		if internal.IsSet(ctx) {
+			t.Error("internal.IsSet() = true, want non-propagatable key to be ignored")
+		}
+	})
+
+	t.Run("malformed members and invalid values are reported", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx, err := registry.DecodeBaggage(context.Background(), "max-items=many,no-value,region=eu,(bad)=1")
+
+		var parseErr *feature.ParseError
+		if !errors.As(err, &parseErr) || parseErr.Key != "max-items" {
+			t.Errorf("DecodeBaggage() error = %v, want *feature.ParseError for max-items", err)
+		}
+
+		if !errors.Is(err, feature.ErrMalformedBaggage) {
+			t.Errorf("DecodeBaggage() error = %v, want %v", err, feature.ErrMalformedBaggage)
+		}
+
+		checkContains(t, fmt.Sprint(err), `"no-value"`)
+		checkContains(t, fmt.Sprint(err), `"(bad)"`)
+
+		if maxItems.IsSet(ctx) {
+			t.Error("maxItems.IsSet() = true, want invalid value to be skipped")
+		}
+
+		if got := region.Get(ctx); got != "eu" {
+			t.Errorf("region.Get() = %q, want %q", got, "eu")
+		}
+	})
+
+	t.Run("anonymous and non-token names are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		anonymous := feature.NewBool(feature.WithRegistry(registry), feature.WithPropagation())
+		spaced := feature.NewNamedBool("new ui", feature.WithRegistry(registry), feature.WithPropagation())
+
+		_, err := registry.EncodeBaggage(anonymous.WithEnabled(context.Background()))
+		if !errors.Is(err, feature.ErrInvalidBaggageKey) {
+			t.Errorf("EncodeBaggage() error = %v, want %v", err, feature.ErrInvalidBaggageKey)
+		}
+
+		_, err = registry.EncodeBaggage(spaced.WithEnabled(context.Background()))
+		if !errors.Is(err, feature.ErrInvalidBaggageKey) {
+			t.Errorf("EncodeBaggage() error = %v, want %v", err, feature.ErrInvalidBaggageKey)
+		}
+
+		if baggage, err := registry.EncodeBaggage(context.Background()); err != nil || baggage != "" {
+			t.Errorf("EncodeBaggage() = %q, %v, want empty baggage for unset keys", baggage, err)
+		}
+	})
+
+	t.Run("anonymous keys are checked without hooks and overrides", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+
+		registry := feature.NewRegistry()
+		anonymous := feature.NewBool(feature.WithRegistry(registry), feature.WithPropagation())
+
+		defer registry.AddHook(func(context.Context, feature.AnyInspection) { calls++ })()
+
+		restore := feature.Override(anonymous, true)
+		defer restore()
+
+		if baggage, err := registry.EncodeBaggage(context.Background()); err != nil || baggage != "" {
+			t.Errorf("EncodeBaggage() = %q, %v, want empty baggage for overridden keys", baggage, err)
+		}
+
+		if calls != 0 {
+			t.Errorf("hook called %d times, want 0", calls)
+		}
+	})
+
+	t.Run("size limits are enforced", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		ctx := context.Background()
+
+		for idx := 0; idx <= feature.MaxBaggageMembers; idx++ {
+			key := feature.NewNamedBool(fmt.Sprintf("flag-%d", idx), feature.WithRegistry(registry), feature.WithPropagation())
+			ctx = key.WithEnabled(ctx)
+		}
+
+		if _, err := registry.EncodeBaggage(ctx); !errors.Is(err, feature.ErrBaggageTooLarge) {
+			t.Errorf("EncodeBaggage() error = %v, want %v for too many members", err, feature.ErrBaggageTooLarge)
+		}
+
+		registry = feature.NewRegistry()
+		blob := feature.NewNamed[string]("blob", feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx = blob.WithValue(context.Background(), strings.Repeat("x", feature.MaxBaggageBytes))
+		if _, err := registry.EncodeBaggage(ctx); !errors.Is(err, feature.ErrBaggageTooLarge) {
+			t.Errorf("EncodeBaggage() error = %v, want %v for too many bytes", err, feature.ErrBaggageTooLarge)
+		}
+	})
+}
+
+func ExampleRegistry_EncodeBaggage() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		Greeting    = feature.NewNamed[string]("greeting", feature.WithRegistry(registry), feature.WithPropagation())
+	)
+
+	ctx := context.Background()
+	ctx = EnableNewUI.WithEnabled(ctx)
+	ctx = Greeting.WithValue(ctx, "hello, world")
+
+	baggage, _ := registry.EncodeBaggage(ctx)
+	fmt.Println(baggage)
+
+	decoded, _ := registry.DecodeBaggage(context.Background(), "userId=alice,"+baggage)
+	fmt.Println(EnableNewUI.Inspect(decoded))
+	fmt.Println(Greeting.Inspect(decoded))
+
+	// Output:
+	// new-ui=true,greeting=hello%2C%20world
+	// new-ui: true
+	// greeting: hello, world
+}
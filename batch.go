@@ -0,0 +1,129 @@
+package feature
+
+import (
+	"context"
+	"errors"
+)
+
+// Binding is a value bound to a key, created with Key.Bind, BoolKey.BindEnabled or
+// BoolKey.BindDisabled, to be stored in a context together with other bindings by With.
+type Binding struct {
+	ident *opaque
+	value any
+	err   error
+}
+
+// With returns a new context with all the bindings stored in a single context node.
+//
+// Unlike chaining WithValue, which adds one node per key so that lookups walk a chain as deep
+// as the number of keys set, the bindings are stored in a map keyed by key identity, so looking
+// up any of them costs the same. Values set in the returned context later, by WithValue or With,
+// shadow the bindings, which in turn shadow values set in ctx. If a key is bound several times,
+// the last binding wins.
+//
+// If a value is rejected by a validator given with WithValidator, With panics with the
+// *ValidationError of the first rejected binding. Use TryWith to handle the error instead.
+//
+// Example:
+//
+//	ctx = feature.With(ctx,
+//	    MaxItems.Bind(100),
+//	    Region.Bind("eu"),
+//	    EnableNewUI.BindEnabled(),
+//	)
+func With(ctx context.Context, bindings ...Binding) context.Context {
+	for _, b := range bindings {
+		if b.err != nil {
+			panic(b.err)
+		}
+	}
+
+	return withBindings(ctx, bindings)
+}
+
+// TryWith is like With, but returns the original context and an error joining the
+// *ValidationError of every rejected binding instead of panicking.
+func TryWith(ctx context.Context, bindings ...Binding) (context.Context, error) {
+	var errs []error
+
+	for _, b := range bindings {
+		if b.err != nil {
+			errs = append(errs, b.err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return ctx, err
+	}
+
+	return withBindings(ctx, bindings), nil
+}
+
+// Bind returns a binding of the value to this key for use with With.
+// The value is validated by the validators given with WithValidator when the binding is stored.
+func (k key[V]) Bind(value V) Binding {
+	return Binding{ident: k.ident, value: value, err: k.validate(value)}
+}
+
+// BindEnabled returns a binding that enables this feature flag for use with With.
+func (k boolKey) BindEnabled() Binding {
+	return k.Bind(true)
+}
+
+// BindDisabled returns a binding that disables this feature flag for use with With.
+func (k boolKey) BindDisabled() Binding {
+	return k.Bind(false)
+}
+
+func (k key[V]) bindAny(value any) Binding {
+	return Binding{ident: k.ident, value: value.(V), err: nil} //nolint:forcetypeassert // values originate from this key
+}
+
+// withBindings stores the bindings in a single context node without validation.
+func withBindings(ctx context.Context, bindings []Binding) context.Context {
+	switch len(bindings) {
+	case 0:
+		return ctx
+	case 1:
+		return context.WithValue(ctx, bindings[0].ident, bindings[0].value)
+	}
+
+	values := make(map[*opaque]any, len(bindings))
+	for _, b := range bindings {
+		values[b.ident] = b.value
+	}
+
+	return &batchContext{Context: ctx, values: values}
+}
+
+// batchContext is a context node holding the values of many keys.
+type batchContext struct {
+	context.Context
+
+	values map[*opaque]any
+}
+
+// Value returns the value bound to the key in this node, or looks it up in the parent context.
+func (c *batchContext) Value(key any) any {
+	if ident, ok := key.(*opaque); ok {
+		if value, ok := c.values[ident]; ok {
+			return value
+		}
+	}
+
+	return c.Context.Value(key)
+}
+
+// String returns a description of the context node, like the contexts of the context package.
+func (c *batchContext) String() string {
+	return contextName(c.Context) + ".WithFeatureValues"
+}
+
+// contextName returns the description of a context, as printed by the context package.
+func contextName(ctx context.Context) string {
+	if s, ok := ctx.(interface{ String() string }); ok {
+		return s.String()
+	}
+
+	return "context"
+}
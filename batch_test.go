@@ -0,0 +1,218 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestWith tests storing many values in a single context node.
+func TestWith(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all bindings are retrievable", func(t *testing.T) {
+		t.Parallel()
+
+		maxItems := feature.New[int]()
+		region := feature.New[string]()
+		newUI := feature.NewBool()
+		legacy := feature.NewBool(feature.WithDefault(true))
+
+		ctx := feature.With(context.Background(),
+			maxItems.Bind(100),
+			region.Bind("eu"),
+			newUI.BindEnabled(),
+			legacy.BindDisabled(),
+		)
+
+		if got := maxItems.Get(ctx); got != 100 {
+			t.Errorf("maxItems.Get() = %d, want 100", got)
+		}
+
+		if got := region.Get(ctx); got != "eu" {
+			t.Errorf("region.Get() = %q, want %q", got, "eu")
+		}
+
+		if !newUI.Enabled(ctx) {
+			t.Error("newUI.Enabled() = false, want true")
+		}
+
+		if !legacy.ExplicitlyDisabled(ctx) {
+			t.Error("legacy.ExplicitlyDisabled() = false, want true")
+		}
+
+		if got := legacy.Inspect(ctx).Reason; got != feature.ReasonContextOverride {
+			t.Errorf("legacy.Inspect().Reason = %v, want %v", got, feature.ReasonContextOverride)
+		}
+	})
+
+	t.Run("shadowing", func(t *testing.T) {
+		t.Parallel()
+
+		maxItems := feature.New[int]()
+		region := feature.New[string]()
+		other := feature.New[int]()
+
+		ctx := maxItems.WithValue(context.Background(), 1)
+		ctx = other.WithValue(ctx, 7)
+		ctx = feature.With(ctx, maxItems.Bind(2), region.Bind("eu"), region.Bind("us"))
+
+		if got := maxItems.Get(ctx); got != 2 {
+			t.Errorf("maxItems.Get() = %d, want 2 (batch shadows earlier nodes)", got)
+		}
+
+		if got := region.Get(ctx); got != "us" {
+			t.Errorf("region.Get() = %q, want %q (last binding wins)", got, "us")
+		}
+
+		if got := other.Get(ctx); got != 7 {
+			t.Errorf("other.Get() = %d, want 7 (lookups fall through to the parent)", got)
+		}
+
+		ctx = maxItems.WithValue(ctx, 3)
+		ctx = feature.With(ctx, region.Bind("ap"), other.Bind(8))
+
+		if got := maxItems.Get(ctx); got != 3 {
+			t.Errorf("maxItems.Get() = %d, want 3 (later nodes shadow the batch)", got)
+		}
+
+		if got := region.Get(ctx); got != "ap" {
+			t.Errorf("region.Get() = %q, want %q (newer batch shadows older batch)", got, "ap")
+		}
+	})
+
+	t.Run("no bindings", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		if got := feature.With(ctx); got != ctx {
+			t.Errorf("With() = %v, want the original context", got)
+		}
+	})
+
+	t.Run("non-feature values fall through", func(t *testing.T) {
+		t.Parallel()
+
+		type ctxKey struct{}
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+		ctx = feature.With(ctx, feature.New[int]().Bind(1), feature.New[int]().Bind(2))
+
+		if got := ctx.Value(ctxKey{}); got != "value" {
+			t.Errorf("Value() = %v, want %q", got, "value")
+		}
+	})
+
+	t.Run("decoders store values in a single node", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+
+		const want = "context.Background.WithFeatureValues"
+
+		ctx, err := registry.DecodeHeader(context.Background(), "new-ui=true,max-items=1,max-items=2")
+		if err != nil {
+			t.Fatalf("DecodeHeader() error = %v", err)
+		}
+
+		if got := fmt.Sprint(ctx); got != want {
+			t.Errorf("DecodeHeader() context = %s, want %s", got, want)
+		}
+
+		if !newUI.Enabled(ctx) || maxItems.Get(ctx) != 2 {
+			t.Errorf("DecodeHeader() = %v, %v, want enabled and last value 2", newUI.Inspect(ctx), maxItems.Inspect(ctx))
+		}
+
+		ctx, err = registry.DecodeMetadata(context.Background(), map[string][]string{
+			"feature-new-ui":    {"true"},
+			"feature-max-items": {"1"},
+		})
+		if err != nil {
+			t.Fatalf("DecodeMetadata() error = %v", err)
+		}
+
+		if got := fmt.Sprint(ctx); got != want {
+			t.Errorf("DecodeMetadata() context = %s, want %s", got, want)
+		}
+
+		ctx, err = registry.DecodeBaggage(context.Background(), "new-ui=true,foreign=1,max-items=1")
+		if err != nil {
+			t.Fatalf("DecodeBaggage() error = %v", err)
+		}
+
+		if got := fmt.Sprint(ctx); got != want {
+			t.Errorf("DecodeBaggage() context = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("invalid binding panics", func(t *testing.T) {
+		t.Parallel()
+
+		maxItems := feature.NewNamed[int]("max-items", feature.WithValidator(nonNegative))
+
+		defer func() {
+			var validationErr *feature.ValidationError
+			if err, _ := recover().(error); !errors.As(err, &validationErr) {
+				t.Errorf("With() panicked with %v, want *feature.ValidationError", err)
+			}
+		}()
+
+		feature.With(context.Background(), maxItems.Bind(-1))
+	})
+}
+
+// TestTryWith tests storing many values in a single context node with error handling.
+func TestTryWith(t *testing.T) {
+	t.Parallel()
+
+	maxItems := feature.NewNamed[int]("max-items", feature.WithValidator(nonNegative))
+	minItems := feature.NewNamed[int]("min-items", feature.WithValidator(nonNegative))
+	region := feature.New[string]()
+
+	ctx := context.Background()
+
+	got, err := feature.TryWith(ctx, maxItems.Bind(-1), region.Bind("eu"), minItems.Bind(-2))
+	if got != ctx {
+		t.Errorf("TryWith() context = %v, want the original context", got)
+	}
+
+	checkContains(t, fmt.Sprint(err), "max-items")
+	checkContains(t, fmt.Sprint(err), "min-items")
+
+	got, err = feature.TryWith(ctx, maxItems.Bind(1), region.Bind("eu"))
+	if err != nil {
+		t.Fatalf("TryWith() error = %v", err)
+	}
+
+	if v := maxItems.Get(got); v != 1 {
+		t.Errorf("maxItems.Get() = %d, want 1", v)
+	}
+}
+
+func ExampleWith() {
+	var (
+		MaxItems    = feature.NewNamed[int]("max-items")
+		Region      = feature.NewNamed[string]("region")
+		EnableNewUI = feature.NewNamedBool("new-ui")
+	)
+
+	ctx := feature.With(context.Background(),
+		MaxItems.Bind(100),
+		Region.Bind("eu"),
+		EnableNewUI.BindEnabled(),
+	)
+
+	fmt.Println(MaxItems.Get(ctx))
+	fmt.Println(Region.Get(ctx))
+	fmt.Println(EnableNewUI.Enabled(ctx))
+
+	// Output:
+	// 100
+	// eu
+	// true
+}
@@ -0,0 +1,272 @@
+package feature_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// benchDepths are the numbers of values set in the benchmarked contexts.
+var benchDepths = []int{1, 10, 100} //nolint:gochecknoglobals // shared by benchmarks
+
+// benchContext returns a context holding depth values, with the value of target set first
+// so that lookups walk the whole chain. If batch is true, the values are set with feature.With.
+func benchContext(tb testing.TB, depth int, batch bool, target feature.Binding) context.Context {
+	tb.Helper()
+
+	registry := feature.NewRegistry()
+	bindings := []feature.Binding{target}
+
+	for i := 1; i < depth; i++ {
+		bindings = append(bindings, feature.New[int](feature.WithRegistry(registry)).Bind(i))
+	}
+
+	if batch {
+		return feature.With(context.Background(), bindings...)
+	}
+
+	ctx := context.Background()
+	for _, b := range bindings {
+		ctx = feature.With(ctx, b)
+	}
+
+	return ctx
+}
+
+// benchChains runs the benchmark against contexts of every depth, set value by value and in a batch.
+func benchChains(b *testing.B, target feature.Binding, fn func(b *testing.B, ctx context.Context)) {
+	b.Helper()
+
+	for _, depth := range benchDepths {
+		for _, batch := range []bool{false, true} {
+			name := fmt.Sprintf("depth=%d", depth)
+			if batch {
+				name += "/batch"
+			}
+
+			ctx := benchContext(b, depth, batch, target)
+
+			b.Run(name, func(b *testing.B) {
+				b.ReportAllocs()
+				fn(b, ctx)
+			})
+		}
+	}
+}
+
+func BenchmarkKey(b *testing.B) {
+	registry := feature.NewRegistry()
+	key := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+	target := key.Bind(100)
+
+	b.Run("Get", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.Get(ctx)
+			}
+		})
+	})
+
+	b.Run("TryGet", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_, _ = key.TryGet(ctx)
+			}
+		})
+	})
+
+	b.Run("GetOrDefault", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.GetOrDefault(ctx, 10)
+			}
+		})
+	})
+
+	b.Run("MustGet", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.MustGet(ctx)
+			}
+		})
+	})
+
+	b.Run("IsSet", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.IsSet(ctx)
+			}
+		})
+	})
+
+	b.Run("IsNotSet", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.IsNotSet(ctx)
+			}
+		})
+	})
+
+	b.Run("Inspect", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.Inspect(ctx)
+			}
+		})
+	})
+
+	b.Run("WithValue", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.WithValue(ctx, i)
+			}
+		})
+	})
+
+	b.Run("TryWithValue", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_, _ = key.TryWithValue(ctx, i)
+			}
+		})
+	})
+
+	b.Run("Bind", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = key.Bind(i)
+		}
+	})
+
+	b.Run("With", func(b *testing.B) {
+		other := feature.New[string](feature.WithRegistry(registry))
+
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = feature.With(ctx, key.Bind(i), other.Bind("value"))
+			}
+		})
+	})
+}
+
+func BenchmarkBoolKey(b *testing.B) {
+	registry := feature.NewRegistry()
+	key := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+	target := key.BindEnabled()
+
+	b.Run("Enabled", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.Enabled(ctx)
+			}
+		})
+	})
+
+	b.Run("Disabled", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.Disabled(ctx)
+			}
+		})
+	})
+
+	b.Run("ExplicitlyDisabled", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.ExplicitlyDisabled(ctx)
+			}
+		})
+	})
+
+	b.Run("InspectBool", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.InspectBool(ctx)
+			}
+		})
+	})
+
+	b.Run("WithEnabled", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.WithEnabled(ctx)
+			}
+		})
+	})
+
+	b.Run("WithDisabled", func(b *testing.B) {
+		benchChains(b, target, func(b *testing.B, ctx context.Context) {
+			for i := 0; i < b.N; i++ {
+				_ = key.WithDisabled(ctx)
+			}
+		})
+	})
+
+	b.Run("BindEnabled", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = key.BindEnabled()
+		}
+	})
+
+	b.Run("BindDisabled", func(b *testing.B) {
+		b.ReportAllocs()
+
+		for i := 0; i < b.N; i++ {
+			_ = key.BindDisabled()
+		}
+	})
+}
+
+// TestZeroAllocs tests that the hot accessors perform no heap allocations.
+//
+// It must not run in parallel, so that hooks registered by other tests cannot be observed.
+func TestZeroAllocs(t *testing.T) { //nolint:paralleltest // hooks registered by parallel tests allocate
+	registry := feature.NewRegistry()
+	userID := feature.NewNamed[string]("user-id", feature.WithRegistry(registry))
+	region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+	newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+	checkout := feature.NewNamedBool("checkout", feature.WithRegistry(registry), feature.WithRollout(50, userID))
+	maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithDefault(10))
+	minItems := feature.NewNamed[int]("min-items", feature.WithRegistry(registry), feature.WithRules(
+		feature.Rule[int]{Name: "eu", When: feature.Equals(region, "eu"), Value: 5},
+	))
+	retries := feature.NewNamed[int]("retries", feature.WithRegistry(registry))
+
+	restore := feature.Override(retries, 3)
+	defer restore()
+
+	set := feature.With(context.Background(),
+		userID.Bind("user-1"),
+		region.Bind("eu"),
+		newUI.BindEnabled(),
+		maxItems.Bind(100),
+	)
+	unset := context.Background()
+
+	tests := []struct {
+		name string
+		fn   func()
+	}{
+		{name: "Enabled/set", fn: func() { _ = newUI.Enabled(set) }},
+		{name: "Enabled/unset", fn: func() { _ = newUI.Enabled(unset) }},
+		{name: "Enabled/rollout", fn: func() { _ = checkout.Enabled(set) }},
+		{name: "TryGet/set", fn: func() { _, _ = maxItems.TryGet(set) }},
+		{name: "TryGet/unset", fn: func() { _, _ = maxItems.TryGet(unset) }},
+		{name: "TryGet/override", fn: func() { _, _ = retries.TryGet(unset) }},
+		{name: "Get/set", fn: func() { _ = maxItems.Get(set) }},
+		{name: "Get/default", fn: func() { _ = maxItems.Get(unset) }},
+		{name: "Get/rule", fn: func() { _ = minItems.Get(set) }},
+		{name: "Get/string", fn: func() { _ = region.Get(set) }},
+	}
+
+	for _, tt := range tests {
+		if got := testing.AllocsPerRun(100, tt.fn); got != 0 {
+			t.Errorf("%s: AllocsPerRun() = %v, want 0", tt.name, got)
+		}
+	}
+}
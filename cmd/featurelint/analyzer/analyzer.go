@@ -0,0 +1,391 @@
+// Package analyzer provides the featurelint analyzer, which checks how feature flag keys
+// of github.com/mpyw/feature are declared.
+//
+// It reports:
+//
+//   - keys created outside package-level var declarations, which yield a fresh identity
+//     on each call and therefore never match values stored by other calls
+//   - anonymous keys, i.e. keys created without a name, if the -anonymous flag is set
+//   - literal key names declared more than once, within a package or across packages
+//     linked together
+//   - literal expiry dates given with feature.WithExpiry that have passed, so that stale
+//     flags fail CI
+//
+// Duplicate names across packages are detected through analysis facts: a package reports
+// names that collide with names declared in the packages it imports, as well as collisions
+// between packages it imports that do not import each other.
+package analyzer
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+// featurePkgPath is the import path of the feature package.
+const featurePkgPath = "github.com/mpyw/feature"
+
+// Analyzer is the featurelint analyzer.
+//
+//nolint:gochecknoglobals // analyzers are conventionally exposed as package-level variables
+var Analyzer = &analysis.Analyzer{
+	Name:      "featurelint",
+	Doc:       "check that feature flag keys are package-level, named, uniquely named and not expired",
+	URL:       "https://github.com/mpyw/feature",
+	Flags:     flags(),
+	Run:       run,
+	Requires:  []*analysis.Analyzer{inspect.Analyzer},
+	FactTypes: []analysis.Fact{new(KeyNamesFact)},
+}
+
+// reportAnonymous is set by the -anonymous flag.
+//
+//nolint:gochecknoglobals // bound to the analyzer flag
+var reportAnonymous bool
+
+// flags returns the flags of the analyzer.
+func flags() flag.FlagSet {
+	fs := flag.NewFlagSet("featurelint", flag.ExitOnError)
+	fs.BoolVar(&reportAnonymous, "anonymous", false, "report keys created without a name")
+
+	return *fs
+}
+
+// constructors lists the functions of the feature package that create keys.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var constructors = map[string]bool{
+	"New":          true,
+	"NewBool":      true,
+	"NewEnum":      true,
+	"NewNamed":     true,
+	"NewNamedBool": true,
+}
+
+// KeyName is a literal key name declared in a package.
+type KeyName struct {
+	// Name is the literal name of the key.
+	Name string
+	// Pos is the position of the declaration, formatted as "file:line:column".
+	Pos string
+}
+
+// KeyNamesFact records the literal key names declared in a package and the packages it imports.
+type KeyNamesFact struct {
+	// Names are the first declarations of each name.
+	Names []KeyName
+}
+
+// AFact implements analysis.Fact.
+func (*KeyNamesFact) AFact() {}
+
+// String returns a summary of the fact.
+func (f *KeyNamesFact) String() string {
+	names := make([]string, 0, len(f.Names))
+	for _, n := range f.Names {
+		names = append(names, n.Name)
+	}
+
+	return "keyNames(" + strings.Join(names, ", ") + ")"
+}
+
+// keyCall is a call to a key constructor.
+type keyCall struct {
+	call *ast.CallExpr
+	name string
+	// named is true if the call has a name, literal or not.
+	named bool
+	// literal is true if the name is a compile-time constant.
+	literal bool
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if pass.Pkg.Path() == featurePkgPath {
+		// The constructors delegate to each other.
+		return nil, nil //nolint:nilnil // the analyzer has no result
+	}
+
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector) //nolint:forcetypeassert // guaranteed by Requires
+
+	var calls []keyCall
+
+	insp.WithStack([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node, push bool, stack []ast.Node) bool {
+		if !push {
+			return true
+		}
+
+		call := n.(*ast.CallExpr) //nolint:forcetypeassert // filtered by node type
+
+		fn := calledFunc(pass.TypesInfo, call)
+		if fn == nil {
+			return true
+		}
+
+		if fn.Name() == "WithExpiry" {
+			reportExpired(pass, call)
+
+			return true
+		}
+
+		if !constructors[fn.Name()] {
+			return true
+		}
+
+		kc := inspectKeyCall(pass.TypesInfo, fn, call)
+		calls = append(calls, kc)
+
+		if insideFunction(stack) {
+			pass.Reportf(call.Pos(), "feature.%s must be called in a package-level var declaration: "+
+				"keys created inside functions get a new identity on each call", fn.Name())
+		}
+
+		if reportAnonymous && !kc.named {
+			pass.Reportf(call.Pos(), "feature.%s creates an anonymous key: use feature.WithName or feature.%s",
+				fn.Name(), namedVariant(fn.Name()))
+		}
+
+		return true
+	})
+
+	reportDuplicates(pass, calls)
+
+	return nil, nil //nolint:nilnil // the analyzer has no result
+}
+
+// reportDuplicates reports literal names declared more than once and exports the names as a fact.
+//
+// Each fact holds the names of the package and of everything it imports, keeping the first
+// declaration of each name, so that a collision is reported once: at the declaration if it is
+// in the package being analyzed, or at the package clause of the first package importing both
+// declarations otherwise.
+func reportDuplicates(pass *analysis.Pass, calls []keyCall) {
+	seen := make(map[string]string)
+
+	var names []KeyName
+
+	imports := pass.Pkg.Imports()
+	sort.Slice(imports, func(i, j int) bool { return imports[i].Path() < imports[j].Path() })
+
+	for _, imp := range imports {
+		var fact KeyNamesFact
+		if !pass.ImportPackageFact(imp, &fact) {
+			continue
+		}
+
+		for _, n := range fact.Names {
+			first, dup := seen[n.Name]
+
+			switch {
+			case !dup:
+				seen[n.Name] = n.Pos
+				names = append(names, n)
+			case first != n.Pos:
+				pass.Reportf(packagePos(pass), "key name %q is declared at both %s and %s", n.Name, first, n.Pos)
+			}
+		}
+	}
+
+	for _, kc := range calls {
+		if !kc.literal {
+			continue
+		}
+
+		if first, dup := seen[kc.name]; dup {
+			pass.Reportf(kc.call.Pos(), "key name %q is already declared at %s", kc.name, first)
+
+			continue
+		}
+
+		pos := pass.Fset.Position(kc.call.Pos()).String()
+		seen[kc.name] = pos
+		names = append(names, KeyName{Name: kc.name, Pos: pos})
+	}
+
+	if len(names) > 0 {
+		pass.ExportPackageFact(&KeyNamesFact{Names: names})
+	}
+}
+
+// packagePos returns the position of the package clause of the first file of the package.
+func packagePos(pass *analysis.Pass) token.Pos {
+	if len(pass.Files) == 0 {
+		return token.NoPos
+	}
+
+	return pass.Files[0].Name.Pos()
+}
+
+// calledFunc returns the function of the feature package called by call, if any.
+func calledFunc(info *types.Info, call *ast.CallExpr) *types.Func {
+	fun := ast.Unparen(call.Fun)
+
+	// Strip explicit type arguments, e.g. feature.New[int].
+	switch f := fun.(type) {
+	case *ast.IndexExpr:
+		fun = f.X
+	case *ast.IndexListExpr:
+		fun = f.X
+	}
+
+	var ident *ast.Ident
+
+	switch f := fun.(type) {
+	case *ast.SelectorExpr:
+		ident = f.Sel
+	case *ast.Ident:
+		ident = f
+	default:
+		return nil
+	}
+
+	fn, ok := info.Uses[ident].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != featurePkgPath {
+		return nil
+	}
+
+	return fn
+}
+
+// inspectKeyCall extracts the name given to a key constructor.
+func inspectKeyCall(info *types.Info, fn *types.Func, call *ast.CallExpr) keyCall {
+	kc := keyCall{call: call, name: "", named: false, literal: false}
+
+	args := call.Args
+
+	switch {
+	case strings.HasPrefix(fn.Name(), "NewNamed") && len(args) > 0:
+		kc.named = true
+		kc.name, kc.literal = stringConstant(info, args[0])
+		args = args[1:]
+	case fn.Name() == "NewEnum" && len(args) > 1:
+		// The name is followed by the variants.
+		kc.named = true
+		kc.name, kc.literal = stringConstant(info, args[0])
+		args = args[2:]
+	}
+
+	if call.Ellipsis.IsValid() {
+		// Options are passed as a slice, so the name cannot be determined statically.
+		kc.named = true
+
+		return kc
+	}
+
+	for _, arg := range args {
+		opt, ok := arg.(*ast.CallExpr)
+		if !ok {
+			// An option built elsewhere may set the name.
+			kc.named = true
+
+			continue
+		}
+
+		if optFn := calledFunc(info, opt); optFn != nil && optFn.Name() == "WithName" && len(opt.Args) == 1 {
+			kc.named = true
+			kc.name, kc.literal = stringConstant(info, opt.Args[0])
+		}
+	}
+
+	return kc
+}
+
+// stringConstant returns the value of expr if it is a string constant.
+func stringConstant(info *types.Info, expr ast.Expr) (string, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
+	}
+
+	return constant.StringVal(tv.Value), true
+}
+
+// insideFunction reports whether any node of the stack is a function declaration or literal.
+func insideFunction(stack []ast.Node) bool {
+	for _, n := range stack {
+		switch n.(type) {
+		case *ast.FuncDecl, *ast.FuncLit:
+			return true
+		}
+	}
+
+	return false
+}
+
+// namedVariant returns the constructor that takes a name.
+func namedVariant(name string) string {
+	if strings.HasPrefix(name, "NewNamed") {
+		return name
+	}
+
+	return fmt.Sprintf("NewNamed%s", strings.TrimPrefix(name, "New"))
+}
+
+// reportExpired reports a call to feature.WithExpiry whose literal date has passed.
+func reportExpired(pass *analysis.Pass, call *ast.CallExpr) {
+	if len(call.Args) != 1 {
+		return
+	}
+
+	expiry, ok := literalTime(pass.TypesInfo, call.Args[0])
+	if !ok || time.Now().Before(expiry) {
+		return
+	}
+
+	pass.Reportf(call.Pos(), "feature flag expired on %s: remove the flag or extend its expiry",
+		expiry.Format(time.DateOnly))
+}
+
+// literalTime evaluates calls to time.Date whose arguments are constants.
+// The location given to time.Date is ignored and UTC is assumed.
+func literalTime(info *types.Info, expr ast.Expr) (time.Time, bool) {
+	call, ok := ast.Unparen(expr).(*ast.CallExpr)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	sel, ok := ast.Unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	fn, ok := info.Uses[sel.Sel].(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != "time" || fn.Name() != "Date" || len(call.Args) != 8 {
+		return time.Time{}, false
+	}
+
+	var parts [7]int
+
+	for idx := range parts {
+		v, ok := intConstant(info, call.Args[idx])
+		if !ok {
+			return time.Time{}, false
+		}
+
+		parts[idx] = v
+	}
+
+	return time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], parts[6], time.UTC), true
+}
+
+// intConstant returns the value of expr if it is an integer constant.
+func intConstant(info *types.Info, expr ast.Expr) (int, bool) {
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.Int {
+		return 0, false
+	}
+
+	v, err := strconv.Atoi(tv.Value.ExactString())
+
+	return v, err == nil
+}
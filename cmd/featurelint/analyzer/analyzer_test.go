@@ -0,0 +1,23 @@
+package analyzer_test
+
+import (
+	"testing"
+
+	"golang.org/x/tools/go/analysis/analysistest"
+
+	"github.com/mpyw/feature/cmd/featurelint/analyzer"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "flags", "dup", "app", "expiry")
+}
+
+func TestAnalyzerAnonymous(t *testing.T) {
+	if err := analyzer.Analyzer.Flags.Set("anonymous", "true"); err != nil {
+		t.Fatalf("Flags.Set() error = %v", err)
+	}
+
+	t.Cleanup(func() { _ = analyzer.Analyzer.Flags.Set("anonymous", "false") })
+
+	analysistest.Run(t, analysistest.TestData(), analyzer.Analyzer, "anonymous")
+}
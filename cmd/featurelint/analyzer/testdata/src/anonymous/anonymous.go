@@ -0,0 +1,16 @@
+package anonymous // want package:"keyNames\\(named, option\\)"
+
+import "github.com/mpyw/feature"
+
+var options = []feature.Option{feature.WithName("from-slice")}
+
+var (
+	Anonymous = feature.NewBool()  // want `feature.NewBool creates an anonymous key: use feature.WithName or feature.NewNamedBool`
+	Typed     = feature.New[int]() // want `feature.New creates an anonymous key: use feature.WithName or feature.NewNamed`
+	Named     = feature.NewNamedBool("named")
+	Option    = feature.New[int](feature.WithName("option"))
+	Spread    = feature.NewBool(options...)
+	Variable  = feature.NewBool(nameOption)
+)
+
+var nameOption = feature.WithName("variable")
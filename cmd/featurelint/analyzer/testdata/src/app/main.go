@@ -0,0 +1,8 @@
+package main // want `key name "unique" is declared at both .*dup.go:11:.* and .*other.go:5:` package:"keyNames\\(new-ui, max-items, region, algorithm, computed, level, unique\\)"
+
+import (
+	_ "dup"
+	_ "other"
+)
+
+func main() {}
@@ -0,0 +1,12 @@
+package dup // want package:"keyNames\\(new-ui, max-items, region, algorithm, computed, level, unique\\)"
+
+import (
+	"github.com/mpyw/feature"
+
+	_ "flags"
+)
+
+var (
+	Region = feature.NewNamed[string]("region") // want `key name "region" is already declared at .*flags.go:10:`
+	Unique = feature.NewNamed[string]("unique")
+)
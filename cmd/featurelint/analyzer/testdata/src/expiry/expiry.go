@@ -0,0 +1,16 @@
+package expiry // want package:"keyNames\\(expired, fresh, computed\\)"
+
+import (
+	"time"
+
+	"github.com/mpyw/feature"
+)
+
+var (
+	Expired = feature.NewNamedBool("expired",
+		feature.WithExpiry(time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)), // want `feature flag expired on 2000-01-01: remove the flag or extend its expiry`
+		feature.WithOwner("team-a"),
+	)
+	Fresh    = feature.NewNamedBool("fresh", feature.WithExpiry(time.Date(2999, 12, 31, 0, 0, 0, 0, time.UTC)))
+	Computed = feature.NewNamedBool("computed", feature.WithExpiry(time.Now()))
+)
@@ -0,0 +1,31 @@
+package flags // want package:"keyNames\\(new-ui, max-items, region, algorithm, computed, level\\)"
+
+import "github.com/mpyw/feature"
+
+const maxItemsName = "max-items"
+
+var (
+	NewUI    = feature.NewNamedBool("new-ui")
+	MaxItems = feature.NewNamed[int](maxItemsName, feature.WithDefault(100))
+	Region   = feature.New[string](feature.WithName("region"))
+	Beta     = feature.NewBool()
+
+	Algorithm = feature.NewEnum("algorithm", []string{"v1", "v2"}, feature.WithDefault("v1"))
+)
+
+var Computed = func() feature.BoolKey {
+	return feature.NewNamedBool("computed") // want `feature.NewNamedBool must be called in a package-level var declaration`
+}()
+
+func Handler() bool {
+	key := feature.NewBool() // want `feature.NewBool must be called in a package-level var declaration`
+
+	return key != nil
+}
+
+func Generic() {
+	_ = feature.New[int]()                    // want `feature.New must be called in a package-level var declaration`
+	_ = feature.NewEnum("level", []int{1, 2}) // want `feature.NewEnum must be called in a package-level var declaration`
+}
+
+var Duplicate = feature.NewNamedBool("new-ui") // want `key name "new-ui" is already declared at .*flags.go:8:`
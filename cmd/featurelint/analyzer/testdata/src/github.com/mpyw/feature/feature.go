@@ -0,0 +1,30 @@
+// Package feature is a stub of github.com/mpyw/feature for testing the analyzer.
+package feature
+
+import "time"
+
+type Key[V any] interface{ get() V }
+
+type BoolKey interface{ Key[bool] }
+
+type Option func()
+
+func WithName(name string) Option { return nil }
+
+func WithDefault[V any](value V) Option { return nil }
+
+func New[V any](options ...Option) Key[V] { return nil }
+
+func NewNamed[V any](name string, options ...Option) Key[V] { return nil }
+
+func NewBool(options ...Option) BoolKey { return nil }
+
+func NewNamedBool(name string, options ...Option) BoolKey { return nil }
+
+type EnumKey[V comparable] interface{ Key[V] }
+
+func NewEnum[V comparable](name string, variants []V, options ...Option) EnumKey[V] { return nil }
+
+func WithExpiry(expiry time.Time) Option { return nil }
+
+func WithOwner(owner string) Option { return nil }
@@ -0,0 +1,5 @@
+package other // want package:"keyNames\\(unique\\)"
+
+import "github.com/mpyw/feature"
+
+var Unique = feature.NewNamed[string]("unique")
@@ -0,0 +1,22 @@
+// Command featurelint checks that feature flag keys of github.com/mpyw/feature are declared
+// as package-level variables with unique names.
+//
+// Usage:
+//
+//	go install github.com/mpyw/feature/cmd/featurelint@latest
+//	featurelint [-anonymous] ./...
+//
+// It can also be run through go vet:
+//
+//	go vet -vettool=$(which featurelint) ./...
+package main
+
+import (
+	"golang.org/x/tools/go/analysis/singlechecker"
+
+	"github.com/mpyw/feature/cmd/featurelint/analyzer"
+)
+
+func main() {
+	singlechecker.Main(analyzer.Analyzer)
+}
@@ -0,0 +1,198 @@
+package feature
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ErrUnsupportedType is returned when a key's value type has no textual representation.
+var ErrUnsupportedType = errors.New("unsupported value type")
+
+// ParseError is returned when a textual value cannot be converted to the value type of a key.
+type ParseError struct {
+	// Key is the name of the key the value was parsed for.
+	Key string
+	// Input is the text that failed to parse.
+	Input string
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("parsing %q for key %s: %v", e.Input, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// FormatError is returned when the value of a key cannot be converted to text.
+type FormatError struct {
+	// Key is the name of the key the value belongs to.
+	Key string
+	// Value is the value that failed to format.
+	Value any
+	// Err is the underlying error.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("formatting value %v for key %s: %v", e.Value, e.Key, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FormatError) Unwrap() error {
+	return e.Err
+}
+
+// durationType is the reflect.Type of time.Duration, which needs special handling
+// because its kind is reflect.Int64.
+//
+//nolint:gochecknoglobals // immutable type descriptor
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// parseText converts text into a value of type V.
+//
+// Supported types are encoding.TextUnmarshaler implementations, time.Duration,
+// and types whose underlying type is bool, a signed or unsigned integer, a float or a string.
+// Integers accept the base prefixes understood by strconv.ParseInt (e.g. "0x1f").
+func parseText[V any](text string) (V, error) {
+	var value V
+
+	if err := unmarshalText(&value, text); err != nil {
+		return value, err
+	}
+
+	return value, nil
+}
+
+// unmarshalText stores the parsed text in the value pointed to by ptr.
+func unmarshalText(ptr any, text string) error {
+	if u, ok := ptr.(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(text)) //nolint:wrapcheck // wrapped by the caller in ParseError
+	}
+
+	rv := reflect.ValueOf(ptr).Elem()
+	typ := rv.Type()
+
+	if typ == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err //nolint:wrapcheck // wrapped by the caller in ParseError
+		}
+
+		rv.SetInt(int64(d))
+
+		return nil
+	}
+
+	//nolint:exhaustive // remaining kinds are reported as unsupported
+	switch typ.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err //nolint:wrapcheck // wrapped by the caller in ParseError
+		}
+
+		rv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 0, typ.Bits())
+		if err != nil {
+			return err //nolint:wrapcheck // wrapped by the caller in ParseError
+		}
+
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(text, 0, typ.Bits())
+		if err != nil {
+			return err //nolint:wrapcheck // wrapped by the caller in ParseError
+		}
+
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, typ.Bits())
+		if err != nil {
+			return err //nolint:wrapcheck // wrapped by the caller in ParseError
+		}
+
+		rv.SetFloat(f)
+	case reflect.String:
+		rv.SetString(text)
+	default:
+		return fmt.Errorf("%w %s", ErrUnsupportedType, typ)
+	}
+
+	return nil
+}
+
+// formatText converts a value of type V into text that parseText converts back.
+func formatText[V any](value V) (string, error) {
+	if m, ok := any(value).(encoding.TextMarshaler); ok {
+		return marshalText(m)
+	}
+
+	if m, ok := any(&value).(encoding.TextMarshaler); ok {
+		return marshalText(m)
+	}
+
+	rv := reflect.ValueOf(&value).Elem()
+	typ := rv.Type()
+
+	if typ == durationType {
+		return time.Duration(rv.Int()).String(), nil
+	}
+
+	//nolint:exhaustive // remaining kinds are reported as unsupported
+	switch typ.Kind() {
+	case reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(rv.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'g', -1, typ.Bits()), nil
+	case reflect.String:
+		return rv.String(), nil
+	default:
+		return "", fmt.Errorf("%w %s", ErrUnsupportedType, typ)
+	}
+}
+
+// supportsText reports whether values of type V can be both parsed by parseText and formatted by formatText.
+func supportsText[V any]() bool {
+	typ := typeOf[V]()
+	ptr := reflect.PointerTo(typ)
+
+	if typ == durationType {
+		return true
+	}
+
+	//nolint:exhaustive // remaining kinds are unsupported
+	switch typ.Kind() {
+	case reflect.Bool, reflect.String, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	default:
+		return ptr.Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) &&
+			ptr.Implements(reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem())
+	}
+}
+
+// marshalText returns the text produced by the marshaler.
+func marshalText(m encoding.TextMarshaler) (string, error) {
+	text, err := m.MarshalText()
+	if err != nil {
+		return "", err //nolint:wrapcheck // wrapped by the caller in FormatError
+	}
+
+	return string(text), nil
+}
@@ -0,0 +1,128 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownVariant is wrapped by the *ValidationError returned when a value outside the
+// variants of an enum key is associated with it.
+var ErrUnknownVariant = errors.New("unknown variant")
+
+// EnumKey is a key restricted to a declared set of values, created with NewEnum.
+//
+// It provides methods to list the variants and to dispatch on the value exhaustively.
+type EnumKey[V comparable] interface {
+	Key[V]
+
+	// Variants returns the values the key accepts, in declaration order.
+	Variants() []V
+
+	// Switch calls the function of cases associated with the value of this key in the context,
+	// including its default value, and reports whether a function was called.
+	// Nothing is called if the key has no value, i.e. it is not set and has no default value.
+	//
+	// Cases must map every variant to a non-nil function and contain nothing else;
+	// otherwise Switch panics, so that adding a variant breaks every call site that does not handle it.
+	Switch(ctx context.Context, cases map[V]func()) bool
+}
+
+// NewEnum creates a new feature flag key with a debug name, restricted to the given variants.
+//
+// Values outside the variants are rejected with a *ValidationError wrapping ErrUnknownVariant
+// wherever values are validated (see WithValidator): by WithValue and TryWithValue, by loaders
+// parsing text such as LoadEnv, LoadFile and BindFlags, and by propagation decoders.
+// NewEnum panics if no variant is given, if a variant is given twice,
+// or if the default value given with WithDefault is not a variant.
+//
+// The variants are taken as a slice rather than variadic arguments, so that options such as
+// WithDefault and WithRegistry can be given as with every other constructor.
+//
+// Example:
+//
+//	var Algorithm = feature.NewEnum("algorithm", []string{"v1", "v2", "v3"}, feature.WithDefault("v1"))
+//
+//	Algorithm.Switch(ctx, map[string]func(){
+//	    "v1": runV1,
+//	    "v2": runV2,
+//	    "v3": runV3,
+//	})
+func NewEnum[V comparable](name string, variants []V, options ...Option) EnumKey[V] {
+	if len(variants) == 0 {
+		panic(fmt.Sprintf("enum key %s requires at least one variant", name))
+	}
+
+	allowed := make(map[V]bool, len(variants))
+	for _, variant := range variants {
+		if allowed[variant] {
+			panic(fmt.Sprintf("enum key %s has duplicate variant %v", name, variant))
+		}
+
+		allowed[variant] = true
+	}
+
+	variants = append([]V(nil), variants...)
+	validator := func(value V) error {
+		if !allowed[value] {
+			return fmt.Errorf("%w, must be one of %v", ErrUnknownVariant, variants)
+		}
+
+		return nil
+	}
+
+	options = appendCallerDepthIncr(options)
+	opts := optionsFrom(append([]Option{WithName(name), WithValidator(validator)}, options...))
+	site := callSite(opts.depth)
+	k := enumKey[V]{key: newKey[V](opts, site), enumConfig: &enumConfig[V]{variants: variants, allowed: allowed}}
+	k.self = k
+	opts.register(k, site)
+
+	return k
+}
+
+// enumKey is the internal implementation of EnumKey.
+type enumKey[V comparable] struct {
+	key[V]
+
+	// enumConfig is shared by all copies of the key.
+	*enumConfig[V]
+}
+
+// enumConfig holds the variants of an enum key.
+type enumConfig[V comparable] struct {
+	variants []V
+	allowed  map[V]bool
+}
+
+// Variants returns the values the key accepts, in declaration order.
+func (k enumKey[V]) Variants() []V {
+	return append([]V(nil), k.variants...)
+}
+
+// Switch calls the function of cases associated with the value of this key in the context.
+// It panics if cases does not map exactly the variants of the key to non-nil functions.
+func (k enumKey[V]) Switch(ctx context.Context, cases map[V]func()) bool {
+	for _, variant := range k.variants {
+		if cases[variant] == nil {
+			panic(fmt.Sprintf("switch on enum key %s is missing variant %v", k.name, variant))
+		}
+	}
+
+	if len(cases) != len(k.variants) {
+		for value := range cases {
+			if !k.allowed[value] {
+				panic(fmt.Sprintf("switch on enum key %s has case %v that is not a variant", k.name, value))
+			}
+		}
+	}
+
+	inspection := k.Inspect(ctx)
+	if inspection.Reason == ReasonNotSet {
+		return false
+	}
+
+	cases[inspection.Value]()
+
+	return true
+}
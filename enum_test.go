@@ -0,0 +1,252 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestNewEnum tests enum keys restricted to a declared set of values.
+func TestNewEnum(t *testing.T) {
+	t.Parallel()
+
+	t.Run("variants", func(t *testing.T) {
+		t.Parallel()
+
+		variants := []string{"v1", "v2", "v3"}
+		algorithm := feature.NewEnum("algorithm", variants)
+		variants[0] = "modified"
+
+		got := algorithm.Variants()
+		if want := []string{"v1", "v2", "v3"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("Variants() = %v, want %v", got, want)
+		}
+
+		got[0] = "modified"
+		if first := algorithm.Variants()[0]; first != "v1" {
+			t.Errorf("Variants()[0] = %q after modifying the result, want %q", first, "v1")
+		}
+	})
+
+	t.Run("WithValue rejects unknown variants", func(t *testing.T) {
+		t.Parallel()
+
+		algorithm := feature.NewEnum("algorithm", []string{"v1", "v2"})
+
+		ctx, err := algorithm.TryWithValue(context.Background(), "v2")
+		if err != nil {
+			t.Fatalf("TryWithValue() error = %v", err)
+		}
+
+		if got := algorithm.Get(ctx); got != "v2" {
+			t.Errorf("Get() = %q, want %q", got, "v2")
+		}
+
+		_, err = algorithm.TryWithValue(context.Background(), "v4")
+
+		var validationErr *feature.ValidationError
+		if !errors.As(err, &validationErr) || !errors.Is(err, feature.ErrUnknownVariant) {
+			t.Errorf("TryWithValue() error = %v, want *feature.ValidationError wrapping ErrUnknownVariant", err)
+		}
+
+		if want := "invalid value v4 for key algorithm: unknown variant, must be one of [v1 v2]"; fmt.Sprint(err) != want {
+			t.Errorf("TryWithValue() error = %q, want %q", err, want)
+		}
+
+		if _, err := feature.TryWith(context.Background(), algorithm.Bind("v4")); !errors.Is(err, feature.ErrUnknownVariant) {
+			t.Errorf("TryWith() error = %v, want ErrUnknownVariant", err)
+		}
+	})
+
+	t.Run("parsing rejects unknown variants", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		level := feature.NewEnum("level", []int{1, 2, 3}, feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx, err := registry.DecodeHeader(context.Background(), "level=2")
+		if err != nil {
+			t.Fatalf("DecodeHeader() error = %v", err)
+		}
+
+		if got := level.Get(ctx); got != 2 {
+			t.Errorf("Get() = %d, want 2", got)
+		}
+
+		if _, err := registry.DecodeHeader(context.Background(), "level=4"); !errors.Is(err, feature.ErrUnknownVariant) {
+			t.Errorf("DecodeHeader() error = %v, want ErrUnknownVariant", err)
+		}
+	})
+
+	t.Run("inspections and registry report the enum key", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		algorithm := feature.NewEnum("algorithm", []string{"v1", "v2"}, feature.WithRegistry(registry))
+
+		if _, ok := algorithm.Inspect(context.Background()).Key.(feature.EnumKey[string]); !ok {
+			t.Error("Inspect().Key is not a feature.EnumKey[string]")
+		}
+
+		entry, ok := registry.Lookup("algorithm")
+		if !ok {
+			t.Fatal("Lookup() ok = false, want true")
+		}
+
+		if _, ok := entry.Key.(feature.EnumKey[string]); !ok {
+			t.Error("entry.Key is not a feature.EnumKey[string]")
+		}
+
+		if got := filepath.Base(entry.CallSite.File); got != "enum_test.go" {
+			t.Errorf("filepath.Base(entry.CallSite.File) = %q, want %q", got, "enum_test.go")
+		}
+	})
+
+	tests := []struct {
+		name string
+		fn   func()
+		want string
+	}{
+		{
+			name: "no variants",
+			fn:   func() { feature.NewEnum[string]("algorithm", nil) },
+			want: "enum key algorithm requires at least one variant",
+		},
+		{
+			name: "duplicate variant",
+			fn:   func() { feature.NewEnum("algorithm", []string{"v1", "v2", "v1"}) },
+			want: "enum key algorithm has duplicate variant v1",
+		},
+		{
+			name: "default outside variants",
+			fn:   func() { feature.NewEnum("algorithm", []string{"v1", "v2"}, feature.WithDefault("v3")) },
+			want: "invalid value v3 for key algorithm: unknown variant, must be one of [v1 v2]",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run("panics with "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprint(recover()); got != tt.want {
+					t.Errorf("NewEnum() panicked with %q, want %q", got, tt.want)
+				}
+			}()
+
+			tt.fn()
+		})
+	}
+}
+
+// TestEnumKeySwitch tests dispatching on the value of an enum key.
+func TestEnumKeySwitch(t *testing.T) {
+	t.Parallel()
+
+	algorithm := feature.NewEnum("algorithm", []string{"v1", "v2"})
+	withDefault := feature.NewEnum("with-default", []string{"v1", "v2"}, feature.WithDefault("v1"))
+
+	dispatch := func(key feature.EnumKey[string], ctx context.Context) (string, bool) {
+		var got string
+
+		ok := key.Switch(ctx, map[string]func(){
+			"v1": func() { got = "v1" },
+			"v2": func() { got = "v2" },
+		})
+
+		return got, ok
+	}
+
+	t.Run("calls the case of the value", func(t *testing.T) {
+		t.Parallel()
+
+		got, ok := dispatch(algorithm, algorithm.WithValue(context.Background(), "v2"))
+		if got != "v2" || !ok {
+			t.Errorf("Switch() called %q, returned %v, want %q, true", got, ok, "v2")
+		}
+	})
+
+	t.Run("calls the case of the default value", func(t *testing.T) {
+		t.Parallel()
+
+		got, ok := dispatch(withDefault, context.Background())
+		if got != "v1" || !ok {
+			t.Errorf("Switch() called %q, returned %v, want %q, true", got, ok, "v1")
+		}
+	})
+
+	t.Run("calls nothing when not set", func(t *testing.T) {
+		t.Parallel()
+
+		got, ok := dispatch(algorithm, context.Background())
+		if got != "" || ok {
+			t.Errorf("Switch() called %q, returned %v, want nothing, false", got, ok)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		cases map[string]func()
+		want  string
+	}{
+		{
+			name:  "missing variant",
+			cases: map[string]func(){"v1": func() {}},
+			want:  "switch on enum key algorithm is missing variant v2",
+		},
+		{
+			name:  "nil case",
+			cases: map[string]func(){"v1": func() {}, "v2": nil},
+			want:  "switch on enum key algorithm is missing variant v2",
+		},
+		{
+			name:  "unknown case",
+			cases: map[string]func(){"v1": func() {}, "v2": func() {}, "v3": func() {}},
+			want:  "switch on enum key algorithm has case v3 that is not a variant",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run("panics with "+tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			defer func() {
+				if got := fmt.Sprint(recover()); got != tt.want {
+					t.Errorf("Switch() panicked with %q, want %q", got, tt.want)
+				}
+			}()
+
+			algorithm.Switch(algorithm.WithValue(context.Background(), "v1"), tt.cases)
+		})
+	}
+}
+
+func ExampleNewEnum() {
+	var Algorithm = feature.NewEnum("algorithm", []string{"v1", "v2", "v3"}, feature.WithDefault("v1"))
+
+	fmt.Println(Algorithm.Variants())
+
+	_, err := Algorithm.TryWithValue(context.Background(), "v4")
+	fmt.Println(err)
+
+	ctx := Algorithm.WithValue(context.Background(), "v2")
+	Algorithm.Switch(ctx, map[string]func(){
+		"v1": func() { fmt.Println("running v1") },
+		"v2": func() { fmt.Println("running v2") },
+		"v3": func() { fmt.Println("running v3") },
+	})
+
+	// Output:
+	// [v1 v2 v3]
+	// invalid value v4 for key algorithm: unknown variant, must be one of [v1 v2 v3]
+	// running v2
+}
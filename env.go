@@ -0,0 +1,82 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadEnv returns a new context with values read from environment variables
+// for every named key recorded in the Registry.
+//
+// The environment variable of a key is the prefix followed by the key name converted
+// to upper case, with every character other than ASCII letters and digits replaced by
+// an underscore. For example, with prefix "APP_FEATURE_", the key "max-items" is read
+// from APP_FEATURE_MAX_ITEMS. Anonymous keys and keys whose variable is not defined
+// are left untouched.
+//
+// Values are parsed according to the key's value type: encoding.TextUnmarshaler
+// implementations, time.Duration, and types whose underlying type is bool, an integer,
+// a float or a string are supported.
+//
+// If some values cannot be parsed, LoadEnv still returns a context containing the values
+// that could be parsed, together with an error joining a *ParseError for each
+// offending key.
+func (r *Registry) LoadEnv(ctx context.Context, prefix string) (context.Context, error) {
+	var (
+		bindings []Binding
+		errs     []error
+	)
+
+	for _, entry := range r.snapshot() {
+		if entry.Anonymous {
+			continue
+		}
+
+		name := envName(prefix, entry.Name)
+
+		text, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+
+		value, err := entry.Key.parseAny(text)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("environment variable %s: %w", name, err))
+
+			continue
+		}
+
+		bindings = append(bindings, entry.Key.bindAny(value))
+	}
+
+	return withBindings(ctx, bindings), errors.Join(errs...)
+}
+
+// LoadEnv returns a new context with values read from environment variables
+// for every named key recorded in the DefaultRegistry.
+// It is equivalent to DefaultRegistry().LoadEnv(ctx, prefix).
+//
+// Example:
+//
+//	// APP_FEATURE_MAX_ITEMS=100 APP_FEATURE_NEW_UI=true ./server
+//	ctx, err := feature.LoadEnv(context.Background(), "APP_FEATURE_")
+func LoadEnv(ctx context.Context, prefix string) (context.Context, error) {
+	return DefaultRegistry().LoadEnv(ctx, prefix)
+}
+
+// envName returns the environment variable name for the key name.
+func envName(prefix, name string) string {
+	return prefix + strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z':
+			return r - 'a' + 'A'
+		case 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
@@ -0,0 +1,215 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"os"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/mpyw/feature"
+)
+
+type plan string
+
+// TestLoadEnv tests loading values from environment variables.
+//
+//nolint:paralleltest // t.Setenv cannot be used with t.Parallel
+func TestLoadEnv(t *testing.T) {
+	t.Run("parses supported types", func(t *testing.T) {
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+		mask := feature.NewNamed[uint8]("mask", feature.WithRegistry(registry))
+		ratio := feature.NewNamed[float64]("ratio", feature.WithRegistry(registry))
+		timeout := feature.NewNamed[time.Duration]("timeout", feature.WithRegistry(registry))
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+		tier := feature.NewNamed[plan]("plan", feature.WithRegistry(registry))
+		addr := feature.NewNamed[netip.Addr]("upstream.addr", feature.WithRegistry(registry))
+
+		t.Setenv("ENV_TEST_NEW_UI", "true")
+		t.Setenv("ENV_TEST_MAX_ITEMS", "100")
+		t.Setenv("ENV_TEST_MASK", "0x1f")
+		t.Setenv("ENV_TEST_RATIO", "0.25")
+		t.Setenv("ENV_TEST_TIMEOUT", "1m30s")
+		t.Setenv("ENV_TEST_REGION", "eu")
+		t.Setenv("ENV_TEST_PLAN", "pro")
+		t.Setenv("ENV_TEST_UPSTREAM_ADDR", "192.0.2.1")
+
+		ctx, err := registry.LoadEnv(context.Background(), "ENV_TEST_")
+		if err != nil {
+			t.Fatalf("LoadEnv() error = %v", err)
+		}
+
+		if !newUI.Enabled(ctx) {
+			t.Error("newUI.Enabled() = false, want true")
+		}
+
+		if got := maxItems.Get(ctx); got != 100 {
+			t.Errorf("maxItems.Get() = %d, want 100", got)
+		}
+
+		if got := mask.Get(ctx); got != 0x1f {
+			t.Errorf("mask.Get() = %d, want 31", got)
+		}
+
+		if got := ratio.Get(ctx); got != 0.25 {
+			t.Errorf("ratio.Get() = %v, want 0.25", got)
+		}
+
+		if got := timeout.Get(ctx); got != 90*time.Second {
+			t.Errorf("timeout.Get() = %v, want 1m30s", got)
+		}
+
+		if got := region.Get(ctx); got != "eu" {
+			t.Errorf("region.Get() = %q, want %q", got, "eu")
+		}
+
+		if got := tier.Get(ctx); got != "pro" {
+			t.Errorf("tier.Get() = %q, want %q", got, "pro")
+		}
+
+		if got := addr.Get(ctx); got != netip.MustParseAddr("192.0.2.1") {
+			t.Errorf("addr.Get() = %v, want 192.0.2.1", got)
+		}
+	})
+
+	t.Run("undefined variables leave keys unset", func(t *testing.T) {
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+
+		ctx, err := registry.LoadEnv(context.Background(), "ENV_TEST_UNDEFINED_")
+		if err != nil {
+			t.Fatalf("LoadEnv() error = %v", err)
+		}
+
+		if newUI.IsSet(ctx) {
+			t.Error("IsSet() = true, want false for undefined variable")
+		}
+	})
+
+	t.Run("explicit false is distinguished from unset", func(t *testing.T) {
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+
+		t.Setenv("ENV_TEST_FALSE_NEW_UI", "0")
+
+		ctx, err := registry.LoadEnv(context.Background(), "ENV_TEST_FALSE_")
+		if err != nil {
+			t.Fatalf("LoadEnv() error = %v", err)
+		}
+
+		if !newUI.ExplicitlyDisabled(ctx) {
+			t.Error("ExplicitlyDisabled() = false, want true")
+		}
+	})
+
+	t.Run("anonymous keys are skipped", func(t *testing.T) {
+		registry := feature.NewRegistry()
+		anonymous := feature.New[string](feature.WithRegistry(registry))
+
+		t.Setenv("ENV_TEST_ANONYMOUS", "value")
+
+		ctx, err := registry.LoadEnv(context.Background(), "ENV_TEST_")
+		if err != nil {
+			t.Fatalf("LoadEnv() error = %v", err)
+		}
+
+		if anonymous.IsSet(ctx) {
+			t.Error("IsSet() = true, want false for anonymous key")
+		}
+	})
+
+	t.Run("parse errors are aggregated", func(t *testing.T) {
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+		t.Setenv("ENV_TEST_ERR_MAX_ITEMS", "many")
+		t.Setenv("ENV_TEST_ERR_NEW_UI", "yes please")
+		t.Setenv("ENV_TEST_ERR_REGION", "eu")
+
+		ctx, err := registry.LoadEnv(context.Background(), "ENV_TEST_ERR_")
+		if err == nil {
+			t.Fatal("LoadEnv() error = nil, want error")
+		}
+
+		checkContains(t, err.Error(), "ENV_TEST_ERR_MAX_ITEMS")
+		checkContains(t, err.Error(), `parsing "many" for key max-items`)
+		checkContains(t, err.Error(), "ENV_TEST_ERR_NEW_UI")
+		checkContains(t, err.Error(), `parsing "yes please" for key new-ui`)
+
+		var parseErr *feature.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Fatalf("errors.As(%v, *feature.ParseError) = false, want true", err)
+		}
+
+		if !errors.Is(err, strconv.ErrSyntax) {
+			t.Errorf("errors.Is(%v, strconv.ErrSyntax) = false, want true", err)
+		}
+
+		if maxItems.IsSet(ctx) || newUI.IsSet(ctx) {
+			t.Error("invalid values were applied to the context")
+		}
+
+		if got := region.Get(ctx); got != "eu" {
+			t.Errorf("region.Get() = %q, want valid values to be applied", got)
+		}
+	})
+
+	t.Run("unsupported types are reported", func(t *testing.T) {
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[[]string]("tags", feature.WithRegistry(registry))
+
+		t.Setenv("ENV_TEST_UNSUPPORTED_TAGS", "a,b")
+
+		_, err := registry.LoadEnv(context.Background(), "ENV_TEST_UNSUPPORTED_")
+		if !errors.Is(err, feature.ErrUnsupportedType) {
+			t.Errorf("LoadEnv() error = %v, want %v", err, feature.ErrUnsupportedType)
+		}
+	})
+
+	t.Run("package-level LoadEnv uses DefaultRegistry", func(t *testing.T) {
+		key := feature.NewNamed[int]("env-test-default", feature.WithRegistry(feature.DefaultRegistry()))
+
+		t.Setenv("ENV_TEST_DEFAULT_ENV_TEST_DEFAULT", "42")
+
+		ctx, err := feature.LoadEnv(context.Background(), "ENV_TEST_DEFAULT_")
+		if err != nil {
+			t.Fatalf("LoadEnv() error = %v", err)
+		}
+
+		if got := key.Get(ctx); got != 42 {
+			t.Errorf("Get() = %d, want 42", got)
+		}
+	})
+}
+
+func ExampleRegistry_LoadEnv() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+	)
+
+	// Usually set outside the process: APP_FEATURE_NEW_UI=true APP_FEATURE_MAX_ITEMS=100
+	_ = os.Setenv("APP_FEATURE_NEW_UI", "true")
+	_ = os.Setenv("APP_FEATURE_MAX_ITEMS", "100")
+
+	ctx, err := registry.LoadEnv(context.Background(), "APP_FEATURE_")
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(EnableNewUI.Inspect(ctx))
+	fmt.Println(MaxItems.Inspect(ctx))
+
+	// Output:
+	// new-ui: true
+	// max-items: 100
+}
@@ -0,0 +1,98 @@
+package feature
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// WithExpiry returns an option that records the date after which the key is considered stale
+// and should be removed from the code base.
+//
+// The expiry does not change how the key is evaluated. It is reported in Entry.Expiry,
+// queried with Registry.Expired, and evaluating the key past its expiry calls the hooks added
+// with AddExpiredHook. The featurelint analyzer also reports literal expiry dates that have passed.
+//
+// Example:
+//
+//	var NewCheckout = feature.NewNamedBool("new-checkout",
+//	    feature.WithExpiry(time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)),
+//	    feature.WithOwner("team-payments"),
+//	)
+func WithExpiry(expiry time.Time) Option {
+	return func(o *options) {
+		o.expiry = expiry
+	}
+}
+
+// WithOwner returns an option that records who is responsible for the key,
+// e.g. a team or a person to contact when the key expires.
+// The owner is reported in Entry.Owner and AnyInspection.Owner.
+func WithOwner(owner string) Option {
+	return func(o *options) {
+		o.owner = owner
+	}
+}
+
+// IsExpired returns true if the key has an expiry that is not after now.
+func (e Entry) IsExpired(now time.Time) bool {
+	return !e.Expiry.IsZero() && !now.Before(e.Expiry)
+}
+
+// Expired returns the entries of the keys whose expiry is not after now, in registration order.
+//
+// Example:
+//
+//	for _, entry := range registry.Expired(time.Now()) {
+//	    log.Printf("flag %s owned by %s expired on %s", entry.Name, entry.Owner, entry.Expiry.Format(time.DateOnly))
+//	}
+func (r *Registry) Expired(now time.Time) []Entry {
+	var expired []Entry
+
+	for _, entry := range r.snapshot() {
+		if entry.IsExpired(now) {
+			expired = append(expired, entry)
+		}
+	}
+
+	return expired
+}
+
+//nolint:gochecknoglobals // expired hooks are intentionally process-wide
+var expiredHooks hookSet
+
+// AddExpiredHook registers a hook called on each evaluation of any key past its expiry
+// given with WithExpiry, in addition to the regular hooks.
+// It returns a function that removes the hook; calling it more than once has no effect.
+//
+// Example:
+//
+//	remove := feature.AddExpiredHook(feature.LogExpired(slog.Default()))
+//	defer remove()
+func AddExpiredHook(hook Hook) (remove func()) {
+	return expiredHooks.add(hook)
+}
+
+// LogExpired returns a hook for AddExpiredHook that logs a warning with the name, expiry and
+// owner of the evaluated key.
+func LogExpired(logger *slog.Logger) Hook {
+	return func(ctx context.Context, inspection AnyInspection) {
+		logger.LogAttrs(ctx, slog.LevelWarn, "expired feature flag evaluated",
+			slog.String("name", inspection.Name()),
+			slog.Time("expiry", inspection.Expiry()),
+			slog.String("owner", inspection.Owner()),
+		)
+	}
+}
+
+// hasExpiredHooks reports whether the key has an expiry and expired hooks are registered.
+func (k key[V]) hasExpiredHooks() bool {
+	return !k.expiry.IsZero() && len(expiredHooks.load()) > 0
+}
+
+// runExpiredHooks calls the expired hooks if the key is past its expiry.
+func (k key[V]) runExpiredHooks(ctx context.Context, inspection AnyInspection) {
+	if k.hasExpiredHooks() && !time.Now().Before(k.expiry) {
+		expiredHooks.run(ctx, inspection)
+	}
+}
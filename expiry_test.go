@@ -0,0 +1,133 @@
+package feature_test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mpyw/feature"
+)
+
+// TestWithExpiry tests recording and querying expiry metadata.
+func TestWithExpiry(t *testing.T) {
+	t.Parallel()
+
+	past := time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+	future := time.Now().AddDate(100, 0, 0)
+
+	t.Run("metadata is recorded and expired keys are queried", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		stale := feature.NewNamedBool("stale",
+			feature.WithRegistry(registry), feature.WithExpiry(past), feature.WithOwner("team-a"))
+		_ = feature.NewNamedBool("fresh", feature.WithRegistry(registry), feature.WithExpiry(future))
+		_ = feature.NewNamedBool("permanent", feature.WithRegistry(registry))
+
+		expired := registry.Expired(time.Now())
+		if len(expired) != 1 || expired[0].Name != "stale" {
+			t.Fatalf("Expired() = %v, want [stale]", expired)
+		}
+
+		if got := expired[0]; !got.Expiry.Equal(past) || got.Owner != "team-a" || got.Key != stale {
+			t.Errorf("Expired()[0] = %+v, want expiry %v and owner team-a", got, past)
+		}
+
+		if got := registry.Expired(past); len(got) != 1 {
+			t.Errorf("Expired(expiry) = %v, want keys to expire at their expiry", got)
+		}
+
+		if got := registry.Expired(past.Add(-time.Nanosecond)); len(got) != 0 {
+			t.Errorf("Expired(before expiry) = %v, want none", got)
+		}
+
+		inspection := registry.Snapshot(context.Background())[0]
+		if !inspection.Expiry().Equal(past) || inspection.Owner() != "team-a" {
+			t.Errorf("Snapshot()[0] expiry, owner = %v, %q, want %v, team-a", inspection.Expiry(), inspection.Owner(), past)
+		}
+	})
+
+	t.Run("expired hooks fire only for expired keys", func(t *testing.T) {
+		t.Parallel()
+
+		stale := feature.NewNamedBool("stale", feature.WithExpiry(past))
+		fresh := feature.NewNamedBool("fresh", feature.WithExpiry(future))
+
+		var staleCalls, freshCalls atomic.Int32
+
+		remove := feature.AddExpiredHook(func(_ context.Context, inspection feature.AnyInspection) {
+			switch inspection.Key() {
+			case stale:
+				staleCalls.Add(1)
+			case fresh:
+				freshCalls.Add(1)
+			}
+		})
+
+		ctx := context.Background()
+		_ = stale.Enabled(ctx)
+		_, _ = stale.TryGet(ctx)
+		_ = fresh.Enabled(ctx)
+
+		remove()
+
+		_ = stale.Enabled(ctx)
+
+		if got := staleCalls.Load(); got != 2 {
+			t.Errorf("expired hook called %d times for stale key, want 2", got)
+		}
+
+		if got := freshCalls.Load(); got != 0 {
+			t.Errorf("expired hook called %d times for fresh key, want 0", got)
+		}
+	})
+
+	t.Run("LogExpired logs a warning", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamedBool("stale",
+			feature.WithRegistry(registry), feature.WithExpiry(past), feature.WithOwner("team-a"))
+		hook := feature.LogExpired(newTestLogger(&buf, nil))
+
+		hook(context.Background(), registry.Snapshot(context.Background())[0])
+
+		want := `level=WARN msg="expired feature flag evaluated" name=stale expiry=2000-01-01T00:00:00.000Z owner=team-a`
+		checkContains(t, buf.String(), want)
+	})
+}
+
+func ExampleRegistry_Expired() {
+	registry := feature.NewRegistry()
+
+	var (
+		_ = feature.NewNamedBool("new-checkout",
+			feature.WithRegistry(registry),
+			feature.WithExpiry(time.Date(2025, time.March, 31, 0, 0, 0, 0, time.UTC)),
+			feature.WithOwner("team-payments"),
+		)
+		_ = feature.NewNamedBool("dark-mode", feature.WithRegistry(registry))
+	)
+
+	now := time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)
+	for _, entry := range registry.Expired(now) {
+		fmt.Printf("%s (owner: %s) expired on %s\n", entry.Name, entry.Owner, entry.Expiry.Format(time.DateOnly))
+	}
+
+	// Output:
+	// new-checkout (owner: team-payments) expired on 2025-03-31
+}
+
+func ExampleLogExpired() {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	remove := feature.AddExpiredHook(feature.LogExpired(logger))
+	defer remove()
+}
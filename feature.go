@@ -61,10 +61,66 @@ package feature
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 	"runtime"
+	"time"
 )
 
+// AnyKey is the type-erased view shared by every Key[V] and BoolKey.
+//
+// It allows keys with different value types to be handled together, for example
+// when enumerating the keys recorded in a Registry. Use a type assertion to recover
+// the typed key:
+//
+//	if k, ok := entry.Key.(feature.Key[int]); ok {
+//	    fmt.Println(k.Get(ctx))
+//	}
+type AnyKey interface {
+	// IsSet returns true if this key has been set in the context.
+	IsSet(ctx context.Context) bool
+
+	// IsNotSet returns true if this key has not been set in the context.
+	IsNotSet(ctx context.Context) bool
+
+	fmt.Stringer
+
+	fmt.GoStringer
+
+	// valueType is an internal method used to retrieve the value type V of the key.
+	// also used for sealing the interface.
+	valueType() reflect.Type
+
+	// inspectAny is an internal method used to inspect the key without knowing V.
+	inspectAny(ctx context.Context) AnyInspection
+
+	// parseAny is an internal method used to parse text into a value of type V.
+	parseAny(text string) (any, error)
+
+	// decodeAny is an internal method used to decode JSON into a value of type V.
+	decodeAny(data []byte) (any, error)
+
+	// tryGetAny is an internal method used to retrieve the value set in the context without knowing V.
+	// Unlike TryGet, it does not call hooks.
+	tryGetAny(ctx context.Context) (any, bool)
+
+	// contextAny is an internal method used to retrieve the value set in the context without knowing V.
+	// Unlike tryGetAny, overrides are not considered.
+	contextAny(ctx context.Context) (any, bool)
+
+	// formatAny is an internal method used to format the value set in the context as text.
+	// It returns false if the key is not set in the context. Overrides are not considered.
+	formatAny(ctx context.Context) (string, bool, error)
+
+	// metadata is an internal method used to retrieve the expiry and owner of the key.
+	metadata() (time.Time, string)
+
+	// bindAny is an internal method used to bind a value of type V without knowing V.
+	// The value must have been obtained from this key, e.g. through parseAny.
+	bindAny(value any) Binding
+}
+
 // Key is a type-safe accessor for feature flags stored in context.Context.
 //
 // Each Key instance is uniquely identified by its pointer address, preventing collisions
@@ -75,25 +131,46 @@ import (
 type Key[V any] interface {
 	// WithValue returns a new context with the given value associated with this key.
 	// The original context is not modified.
+	// If the value is rejected by a validator given with WithValidator, it panics
+	// with a *ValidationError naming the key.
 	WithValue(ctx context.Context, value V) context.Context
 
+	// TryWithValue returns a new context with the given value associated with this key.
+	// If the value is rejected by a validator given with WithValidator, it returns
+	// the original context and a *ValidationError.
+	TryWithValue(ctx context.Context, value V) (context.Context, error)
+
+	// Bind returns a binding of the value to this key, to be stored in a context
+	// together with other bindings by With.
+	Bind(value V) Binding
+
 	// Get retrieves the value associated with this key from the context.
-	// If the key is not set in the context, it returns the zero value of type V.
+	// If the key is not set in the context, it returns the value of the first matching rule
+	// given with WithRules or the default value given with WithDefault,
+	// or the zero value of type V if there is none.
 	Get(ctx context.Context) V
 
 	// TryGet attempts to retrieve the value associated with this key from the context.
-	// It returns the value and a boolean indicating whether the key was set in the context.
+	// It returns the value and a boolean indicating whether the key was set in the context
+	// or forced with Override.
 	// If the key is not set, it returns the zero value of type V and false.
 	TryGet(ctx context.Context) (V, bool)
 
 	// GetOrDefault retrieves the value associated with this key from the context.
-	// If the key is not set, it returns the provided default value.
+	// If the key is not set, it returns the provided default value,
+	// which takes precedence over the default value given with WithDefault.
 	GetOrDefault(ctx context.Context, defaultValue V) V
 
 	// MustGet retrieves the value associated with this key from the context.
-	// If the key is not set, it panics with a descriptive error message.
+	// If the key is not set, it panics with a *NotSetError,
+	// even if the key has a default value.
 	MustGet(ctx context.Context) V
 
+	// Require retrieves the value associated with this key from the context.
+	// If the key is not set, it returns the zero value of type V and a *NotSetError,
+	// which matches ErrNotSet, even if the key has a default value.
+	Require(ctx context.Context) (V, error)
+
 	// IsSet returns true if this key has been set in the context.
 	// It returns false if the key is not present, regardless of what the zero value would be.
 	IsSet(ctx context.Context) bool
@@ -110,6 +187,8 @@ type Key[V any] interface {
 
 	fmt.GoStringer
 
+	AnyKey
+
 	// downcast is an internal method used to retrieve the underlying key implementation.
 	// also used for sealing the interface.
 	downcast() key[V]
@@ -123,7 +202,9 @@ type BoolKey interface {
 	Key[bool]
 
 	// Enabled returns true if the feature flag is set to true in the context.
-	// If the key is not set in the context, it returns false (the zero value).
+	// If the key is not set in the context, it returns the value computed by the rules given
+	// with WithRules, the rollout given with WithRollout or the default value given with WithDefault,
+	// or false (the zero value) if there is none.
 	Enabled(ctx context.Context) bool
 
 	// Disabled returns true if the feature flag is either not set or set to false.
@@ -142,6 +223,12 @@ type BoolKey interface {
 	// The original context is not modified.
 	WithDisabled(ctx context.Context) context.Context
 
+	// BindEnabled returns a binding that enables this feature flag, for use with With.
+	BindEnabled() Binding
+
+	// BindDisabled returns a binding that disables this feature flag, for use with With.
+	BindDisabled() Binding
+
 	// InspectBool retrieves the value from the context and returns a BoolInspection
 	// that provides convenience methods for working with boolean feature flags.
 	InspectBool(ctx context.Context) BoolInspection
@@ -152,7 +239,17 @@ type Option func(*options)
 
 // options configures the behavior of a feature flag key.
 type options struct {
-	name string
+	name         string
+	registry     *Registry
+	defaultValue any
+	hasDefault   bool
+	validators   []any
+	propagated   bool
+	rollout      *rollout
+	rules        []any
+	hooks        []Hook
+	expiry       time.Time
+	owner        string
 
 	// internal use only - tracks the caller depth for name fallback
 	depth int
@@ -171,6 +268,61 @@ func WithName(name string) Option {
 	}
 }
 
+// WithRegistry returns an option that records the key in the given Registry.
+// Registration is opt-in: keys created without this option are not recorded anywhere.
+//
+// Example:
+//
+//	var MaxItems = feature.NewNamed[int]("max-items", feature.WithRegistry(feature.DefaultRegistry()))
+//	entry, _ := feature.DefaultRegistry().Lookup("max-items")
+//	fmt.Println(entry.Type) // Output: int
+func WithRegistry(registry *Registry) Option {
+	return func(o *options) {
+		o.registry = registry
+	}
+}
+
+// WithDefault returns an option that sets the value returned by Get when the key
+// is not set in the context.
+//
+// The default value only affects accessors that resolve a value, such as Get, Enabled
+// and Inspection.Value. TryGet, IsSet and Inspection.Ok still report whether the key
+// was actually set in the context.
+//
+// The type of value must be exactly the value type of the key; otherwise creating
+// the key panics. Specify the type parameter explicitly when the type would be inferred
+// differently, e.g. feature.WithDefault[int64](100).
+//
+// Example:
+//
+//	var MaxItems = feature.NewNamed[int]("max-items", feature.WithDefault(100))
+//	fmt.Println(MaxItems.Get(ctx))     // Output: 100
+//	fmt.Println(MaxItems.Inspect(ctx)) // Output: max-items: 100 (default)
+func WithDefault[V any](value V) Option {
+	return func(o *options) {
+		o.defaultValue = value
+		o.hasDefault = true
+	}
+}
+
+// WithPropagation returns an option that marks the key as propagatable across process
+// boundaries. Only propagatable keys are transmitted and accepted by codecs such as
+// Registry.EncodeHeader and Registry.DecodeHeader, so that internal flags cannot be
+// set by remote peers.
+//
+// The value type of the key must be convertible to and from text: implementations of both
+// encoding.TextMarshaler and encoding.TextUnmarshaler, time.Duration, and types whose underlying
+// type is bool, an integer, a float or a string. Otherwise creating the key panics.
+//
+// Example:
+//
+//	var NewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+func WithPropagation() Option {
+	return func(o *options) {
+		o.propagated = true
+	}
+}
+
 // appendCallerDepthIncr appends an option that increments the caller depth for name fallback.
 // This is used internally to ensure correct caller depth when deriving names from call sites.
 func appendCallerDepthIncr(opts []Option) []Option {
@@ -182,8 +334,18 @@ func appendCallerDepthIncr(opts []Option) []Option {
 // defaultOptions returns a new options with default values.
 func defaultOptions() *options {
 	return &options{
-		name:  "",
-		depth: 0,
+		name:         "",
+		registry:     nil,
+		defaultValue: nil,
+		hasDefault:   false,
+		validators:   nil,
+		propagated:   false,
+		rollout:      nil,
+		rules:        nil,
+		hooks:        nil,
+		expiry:       time.Time{},
+		owner:        "",
+		depth:        0,
 	}
 }
 
@@ -197,24 +359,100 @@ func optionsFrom(opts []Option) *options {
 	return o
 }
 
-func computeKeyName(ident *opaque, name string, depth int) string {
+// register records the key in the configured registry, if any.
+func (o *options) register(k AnyKey, site CallSite) {
+	if o.registry == nil {
+		return
+	}
+
+	o.registry.register(Entry{
+		Key:        k,
+		Name:       k.String(),
+		Type:       k.valueType(),
+		CallSite:   site,
+		Anonymous:  o.name == "",
+		Propagated: o.propagated,
+		Expiry:     o.expiry,
+		Owner:      o.owner,
+	})
+}
+
+// callSite returns the location of the user code that created a key.
+// depth is the number of stack frames added by wrapper functions.
+// Each exported function (New, NewBool, NewNamed, NewNamedBool) calls appendCallerDepthIncr.
+// The call stack is: runtime.Caller -> callSite -> New -> [wrappers...] -> user code
+// Base offset is 1 (callSite itself), plus depth for wrapper functions.
+func callSite(depth int) CallSite {
+	_, file, line, ok := runtime.Caller(1 + depth)
+	if !ok {
+		return CallSite{File: "", Line: 0}
+	}
+
+	return CallSite{File: file, Line: line}
+}
+
+func computeKeyName(ident *opaque, name string, site CallSite) string {
 	// Resolve the base name (handle anonymous keys)
 	if name == "" {
 		// Default fallback
 		name = fmt.Sprintf("anonymous@%p", ident)
 		// Enhance with call site info if available.
-		// depth is the number of stack frames added by wrapper functions.
-		// Each exported function (New, NewBool, NewNamed, NewNamedBool) calls appendCallerDepthIncr.
-		// The call stack is: runtime.Caller -> computeKeyName -> New -> [wrappers...] -> user code
-		// Base offset is 1 (computeKeyName itself), plus depth for wrapper functions.
-		if _, file, line, ok := runtime.Caller(1 + depth); ok {
-			name = fmt.Sprintf("anonymous(%s:%d)@%p", file, line, ident)
+		if site.IsKnown() {
+			name = fmt.Sprintf("anonymous(%s)@%p", site, ident)
 		}
 	}
 
 	return name
 }
 
+// newKey builds the key implementation from resolved options.
+func newKey[V any](opts *options, site CallSite) key[V] {
+	ident := new(opaque)
+	name := computeKeyName(ident, opts.name, site)
+
+	defaultValue, ok := opts.defaultValue.(V)
+	if !ok && opts.defaultValue != nil {
+		panic(fmt.Sprintf("default value of type %T is not assignable to key %s of type %s",
+			opts.defaultValue, name, typeOf[V]()))
+	}
+
+	if opts.propagated && !supportsText[V]() {
+		panic(fmt.Sprintf("propagation is not applicable to key %s of unsupported value type %s", name, typeOf[V]()))
+	}
+
+	k := key[V]{
+		name:  name,
+		ident: ident,
+		keyConfig: &keyConfig[V]{
+			defaultValue: defaultValue,
+			hasDefault:   opts.hasDefault,
+			validators:   validatorsFrom[V](name, opts.validators),
+			rollout:      rolloutFrom[V](opts, name),
+			rules:        rulesFrom[V](name, opts.rules),
+			hooks:        opts.hooks,
+			registry:     opts.registry,
+			expiry:       opts.expiry,
+			owner:        opts.owner,
+			self:         nil,
+		},
+	}
+	k.self = k
+
+	if k.hasDefault {
+		if err := k.validate(defaultValue); err != nil {
+			panic(err)
+		}
+	}
+
+	for _, rule := range k.rules {
+		if err := k.validate(rule.Value); err != nil {
+			panic(fmt.Errorf("rule %s: %w", rule.Name, err))
+		}
+	}
+
+	return k
+}
+
 // NewBool creates a new boolean feature flag key.
 //
 // Each call to NewBool creates a unique key based on pointer identity, preventing collisions.
@@ -231,8 +469,13 @@ func computeKeyName(ident *opaque, name string, depth int) string {
 //	}
 func NewBool(options ...Option) BoolKey {
 	options = appendCallerDepthIncr(options)
+	opts := optionsFrom(options)
+	site := callSite(opts.depth)
+	k := boolKey{key: newKey[bool](opts, site)}
+	k.self = k
+	opts.register(k, site)
 
-	return boolKey{key: New[bool](options...).downcast()}
+	return k
 }
 
 // NewNamedBool creates a new boolean feature flag key with a debug name.
@@ -263,12 +506,11 @@ func NewNamedBool(name string, options ...Option) BoolKey {
 func New[V any](options ...Option) Key[V] {
 	options = appendCallerDepthIncr(options)
 	opts := optionsFrom(options)
-	ident := new(opaque)
+	site := callSite(opts.depth)
+	k := newKey[V](opts, site)
+	opts.register(k, site)
 
-	return key[V]{
-		name:  computeKeyName(ident, opts.name, opts.depth),
-		ident: ident,
-	}
+	return k
 }
 
 // NewNamed creates a new feature flag key for values of type V with a debug name.
@@ -290,6 +532,27 @@ func NewNamed[V any](name string, options ...Option) Key[V] {
 type key[V any] struct {
 	name  string
 	ident *opaque
+
+	// keyConfig is shared by all copies of the key.
+	// It is held by pointer so that keys stay comparable regardless of V.
+	*keyConfig[V]
+}
+
+// keyConfig holds the construction-time configuration of a key.
+type keyConfig[V any] struct {
+	defaultValue V
+	hasDefault   bool
+	validators   []func(V) error
+	rollout      *rollout
+	rules        []Rule[V]
+	hooks        []Hook
+	registry     *Registry
+	expiry       time.Time
+	owner        string
+
+	// self is the public key wrapping the implementation, e.g. a BoolKey,
+	// so that inspections and hooks report the key as it was created.
+	self Key[V]
 }
 
 // boolKey is the internal implementation of BoolKey.
@@ -305,26 +568,135 @@ func (k key[V]) String() string {
 
 // Inspect retrieves the value from the context and returns an Inspection.
 func (k key[V]) Inspect(ctx context.Context) Inspection[V] {
-	val, ok := k.TryGet(ctx)
+	inspection := k.inspect(ctx)
+	k.runHooks(ctx, inspection)
+
+	return inspection
+}
 
-	return Inspection[V]{
-		Key:   k,
-		Value: val,
-		Ok:    ok,
+// inspect evaluates the key without calling hooks.
+func (k key[V]) inspect(ctx context.Context) Inspection[V] {
+	val, reason, ok := k.lookup(ctx)
+	if ok {
+		return Inspection[V]{Key: k.self, Value: val, Ok: true, Reason: reason, Rule: ""}
 	}
+
+	if val, rule, ok := k.evaluateRules(ctx); ok {
+		return Inspection[V]{Key: k.self, Value: val, Ok: false, Reason: ReasonRuleMatch, Rule: rule}
+	}
+
+	if k.rollout != nil {
+		if enabled, ok := k.rollout.evaluate(ctx, k.name); ok {
+			val, _ = any(enabled).(V) // V is always bool for keys with a rollout
+
+			return Inspection[V]{Key: k.self, Value: val, Ok: false, Reason: ReasonRollout, Rule: ""}
+		}
+	}
+
+	if k.hasDefault {
+		return Inspection[V]{Key: k.self, Value: k.defaultValue, Ok: false, Reason: ReasonDefault, Rule: ""}
+	}
+
+	return Inspection[V]{Key: k.self, Value: val, Ok: false, Reason: ReasonNotSet, Rule: ""}
 }
 
 func (k key[V]) downcast() key[V] {
 	return k
 }
 
+func (k key[V]) valueType() reflect.Type {
+	return typeOf[V]()
+}
+
+func (k key[V]) inspectAny(ctx context.Context) AnyInspection {
+	return AnyInspection{inspection: k.inspect(ctx)}
+}
+
+func (k key[V]) parseAny(text string) (any, error) {
+	value, err := parseText[V](text)
+	if err != nil {
+		return nil, &ParseError{Key: k.name, Input: text, Err: err}
+	}
+
+	if err := k.validate(value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (k key[V]) decodeAny(data []byte) (any, error) {
+	var value V
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, &ParseError{Key: k.name, Input: string(data), Err: err}
+	}
+
+	if err := k.validate(value); err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+func (k key[V]) metadata() (time.Time, string) {
+	return k.expiry, k.owner
+}
+
+func (k key[V]) tryGetAny(ctx context.Context) (any, bool) {
+	val, _, ok := k.lookup(ctx)
+
+	return val, ok
+}
+
+func (k key[V]) contextAny(ctx context.Context) (any, bool) {
+	value, ok := ctx.Value(k.ident).(V)
+
+	return value, ok
+}
+
+func (k key[V]) formatAny(ctx context.Context) (string, bool, error) {
+	value, ok := ctx.Value(k.ident).(V)
+	if !ok {
+		return "", false, nil
+	}
+
+	text, err := formatText(value)
+	if err != nil {
+		return "", true, &FormatError{Key: k.name, Value: value, Err: err}
+	}
+
+	return text, true, nil
+}
+
+// store associates the value with this key without validation.
+func (k key[V]) store(ctx context.Context, value V) context.Context {
+	return context.WithValue(ctx, k.ident, value)
+}
+
 // WithValue returns a new context with the given value associated with this key.
+// It panics with a *ValidationError if the value is rejected by a validator.
 func (k key[V]) WithValue(ctx context.Context, value V) context.Context {
-	return context.WithValue(ctx, k.ident, value)
+	ctx, err := k.TryWithValue(ctx, value)
+	if err != nil {
+		panic(err)
+	}
+
+	return ctx
+}
+
+// TryWithValue returns a new context with the given value associated with this key.
+// It returns the original context and a *ValidationError if the value is rejected by a validator.
+func (k key[V]) TryWithValue(ctx context.Context, value V) (context.Context, error) {
+	if err := k.validate(value); err != nil {
+		return ctx, err
+	}
+
+	return k.store(ctx, value), nil
 }
 
 // Get retrieves the value associated with this key from the context.
-// If the key is not set in the context, it returns the zero value of type V.
+// If the key is not set in the context, it returns the default value, if any,
+// or the zero value of type V.
 func (k key[V]) Get(ctx context.Context) V {
 	return k.Inspect(ctx).Get()
 }
@@ -332,7 +704,11 @@ func (k key[V]) Get(ctx context.Context) V {
 // TryGet attempts to retrieve the value associated with this key from the context.
 // It returns the value and a boolean indicating whether the key was set in the context.
 func (k key[V]) TryGet(ctx context.Context) (V, bool) {
-	val, ok := ctx.Value(k.ident).(V)
+	if k.hasHooks() {
+		return k.Inspect(ctx).TryGet()
+	}
+
+	val, _, ok := k.lookup(ctx)
 
 	return val, ok
 }
@@ -344,11 +720,17 @@ func (k key[V]) GetOrDefault(ctx context.Context, defaultValue V) V {
 }
 
 // MustGet retrieves the value associated with this key from the context.
-// If the key is not set, it panics with a descriptive error message.
+// If the key is not set, it panics with a *NotSetError.
 func (k key[V]) MustGet(ctx context.Context) V {
 	return k.Inspect(ctx).MustGet()
 }
 
+// Require retrieves the value associated with this key from the context.
+// If the key is not set, it returns the zero value of type V and a *NotSetError.
+func (k key[V]) Require(ctx context.Context) (V, error) {
+	return k.Inspect(ctx).Require()
+}
+
 // IsSet returns true if this key has been set in the context.
 func (k key[V]) IsSet(ctx context.Context) bool {
 	return k.Inspect(ctx).IsSet()
@@ -2,7 +2,9 @@ package feature_test
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -261,6 +263,79 @@ func TestMustGet(t *testing.T) {
 			t.Errorf("MustGet() = %d, want 0", got)
 		}
 	})
+
+	t.Run("panics with *NotSetError", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-items")
+
+		defer func() {
+			err, _ := recover().(error)
+
+			var notSetErr *feature.NotSetError
+			if !errors.As(err, &notSetErr) {
+				t.Fatalf("MustGet() panicked with %v, want *feature.NotSetError", err)
+			}
+
+			if notSetErr.Key != "max-items" || notSetErr.Type != reflect.TypeOf(0) {
+				t.Errorf("MustGet() panicked with %+v, want key max-items of type int", notSetErr)
+			}
+
+			if !errors.Is(err, feature.ErrNotSet) {
+				t.Errorf("errors.Is(%v, ErrNotSet) = false, want true", err)
+			}
+		}()
+
+		_ = key.MustGet(context.Background())
+	})
+}
+
+// TestRequire tests the Require method.
+func TestRequire(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns value when set", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[string]("region")
+		ctx := key.WithValue(context.Background(), "eu")
+
+		got, err := key.Require(ctx)
+		if err != nil {
+			t.Fatalf("Require() error = %v", err)
+		}
+
+		if got != "eu" {
+			t.Errorf("Require() = %q, want %q", got, "eu")
+		}
+	})
+
+	t.Run("returns *NotSetError when not set", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-items", feature.WithDefault(100))
+
+		got, err := key.Require(context.Background())
+		if got != 0 {
+			t.Errorf("Require() = %d, want 0 even with default", got)
+		}
+
+		if !errors.Is(err, feature.ErrNotSet) {
+			t.Errorf("Require() error = %v, want ErrNotSet", err)
+		}
+
+		if want := "key max-items of type int is not set in context"; fmt.Sprint(err) != want {
+			t.Errorf("Require() error = %q, want %q", err, want)
+		}
+	})
+
+	t.Run("unrelated errors do not match ErrNotSet", func(t *testing.T) {
+		t.Parallel()
+
+		if errors.Is(feature.ErrUnsupportedType, feature.ErrNotSet) {
+			t.Error("errors.Is(ErrUnsupportedType, ErrNotSet) = true, want false")
+		}
+	})
 }
 
 // TestBoolKey tests the specialized BoolKey functionality.
@@ -601,6 +676,126 @@ func TestComplexTypes(t *testing.T) {
 	})
 }
 
+// TestWithDefault tests default values declared at key construction.
+func TestWithDefault(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Get returns default when unset", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		key := feature.NewNamed[int]("max-items", feature.WithDefault(100))
+
+		if got := key.Get(ctx); got != 100 {
+			t.Errorf("Get() = %d, want 100", got)
+		}
+
+		// TryGet and IsSet still report the truth
+		val, ok := key.TryGet(ctx)
+		if ok || val != 0 {
+			t.Errorf("TryGet() = (%d, %v), want (0, false)", val, ok)
+		}
+
+		if key.IsSet(ctx) {
+			t.Error("IsSet() = true, want false for unset key with default")
+		}
+	})
+
+	t.Run("context value takes precedence over default", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.New[int](feature.WithDefault(100))
+		ctx := key.WithValue(context.Background(), 0)
+
+		if got := key.Get(ctx); got != 0 {
+			t.Errorf("Get() = %d, want 0", got)
+		}
+	})
+
+	t.Run("GetOrDefault argument takes precedence over default", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.New[int](feature.WithDefault(100))
+
+		if got := key.GetOrDefault(context.Background(), 5); got != 5 {
+			t.Errorf("GetOrDefault() = %d, want 5", got)
+		}
+	})
+
+	t.Run("MustGet panics even with default", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("required", feature.WithDefault(100))
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("MustGet() did not panic for unset key with default")
+			}
+		}()
+
+		_ = key.MustGet(context.Background())
+	})
+
+	t.Run("BoolKey default", func(t *testing.T) {
+		t.Parallel()
+
+		ctx := context.Background()
+		flag := feature.NewBool(feature.WithDefault(true))
+
+		if !flag.Enabled(ctx) {
+			t.Error("Enabled() = false, want true from default")
+		}
+
+		if flag.ExplicitlyDisabled(ctx) {
+			t.Error("ExplicitlyDisabled() = true, want false for unset flag")
+		}
+
+		ctx = flag.WithDisabled(ctx)
+		if !flag.ExplicitlyDisabled(ctx) {
+			t.Error("ExplicitlyDisabled() = false, want true after WithDisabled")
+		}
+	})
+
+	t.Run("nil default for interface type", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.New[fmt.Stringer](feature.WithDefault[fmt.Stringer](nil))
+
+		if got := key.Get(context.Background()); got != nil {
+			t.Errorf("Get() = %v, want nil", got)
+		}
+	})
+
+	t.Run("keys with non-comparable defaults are comparable", func(t *testing.T) {
+		t.Parallel()
+
+		keyA := feature.New[[]string](feature.WithDefault([]string{"a"}))
+		keyB := feature.New[[]string](feature.WithDefault([]string{"a"}))
+
+		if keyA != keyA || keyA == keyB { //nolint:gocritic // comparing the key with itself is intended
+			t.Error("keys are not compared by identity")
+		}
+	})
+
+	t.Run("mismatched default type panics", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("NewNamed() did not panic for mismatched default type")
+			}
+
+			msg := fmt.Sprint(r)
+			if !strings.Contains(msg, "default value of type int is not assignable to key max-items of type int64") {
+				t.Errorf("panic message = %q, want mention of both types", msg)
+			}
+		}()
+
+		_ = feature.NewNamed[int64]("max-items", feature.WithDefault(100))
+	})
+}
+
 // TestConcurrency tests that keys are safe for concurrent use.
 func TestConcurrency(t *testing.T) {
 	t.Parallel()
@@ -714,6 +909,26 @@ func ExampleWithName() {
 	// api-key
 }
 
+func ExampleWithDefault() {
+	ctx := context.Background()
+
+	// The key owns its default value
+	var MaxItems = feature.NewNamed[int]("max-items", feature.WithDefault(100))
+
+	fmt.Println(MaxItems.Get(ctx))
+	fmt.Println(MaxItems.IsSet(ctx))
+	fmt.Println(MaxItems.Inspect(ctx))
+
+	ctx = MaxItems.WithValue(ctx, 5)
+	fmt.Println(MaxItems.Inspect(ctx))
+
+	// Output:
+	// 100
+	// false
+	// max-items: 100 (default)
+	// max-items: 5
+}
+
 // Key[V] Method Examples
 
 func ExampleKey_WithValue() {
@@ -805,6 +1020,17 @@ func ExampleKey_MustGet() {
 	// Config: production
 }
 
+func ExampleKey_Require() {
+	var RequiredConfig = feature.NewNamed[string]("required-config")
+
+	if _, err := RequiredConfig.Require(context.Background()); errors.Is(err, feature.ErrNotSet) {
+		fmt.Println(err)
+	}
+
+	// Output:
+	// key required-config of type string is not set in context
+}
+
 func ExampleKey_IsSet() {
 	ctx := context.Background()
 
@@ -0,0 +1,160 @@
+// Package featuretest provides helpers for testing code that depends on feature flags.
+//
+// Helpers derive contexts with overridden values and log every override through the
+// test's Logf, so that failing tests show which flags were in effect:
+//
+//	func TestCheckout(t *testing.T) {
+//	    ctx := featuretest.Enable(t, context.Background(), NewCheckout)
+//	    ctx = featuretest.Set(t, ctx, MaxItems, 10)
+//	    // ...
+//	}
+//
+// Matrix runs a subtest for every combination of bool flag states:
+//
+//	featuretest.Matrix(t, func(t *testing.T, ctx context.Context) {
+//	    // runs 9 times: new-ui=unset,beta=unset ... new-ui=disabled,beta=disabled
+//	}, NewUI, Beta)
+package featuretest
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// State is the state of a bool flag in a combination run by Matrix.
+type State int
+
+const (
+	// Unset means that the flag is not set in the context.
+	Unset State = iota
+	// Enabled means that the flag is set to true in the context.
+	Enabled
+	// Disabled means that the flag is explicitly set to false in the context.
+	Disabled
+)
+
+// String returns the name of the state used in subtest names, e.g. "enabled".
+// This implements fmt.Stringer.
+func (s State) String() string {
+	switch s {
+	case Unset:
+		return "unset"
+	case Enabled:
+		return "enabled"
+	case Disabled:
+		return "disabled"
+	default:
+		return "invalid"
+	}
+}
+
+// Apply returns a new context with the flag in this state.
+// For Unset, the context is returned unchanged.
+func (s State) Apply(ctx context.Context, key feature.BoolKey) context.Context {
+	switch s {
+	case Enabled:
+		return key.WithEnabled(ctx)
+	case Disabled:
+		return key.WithDisabled(ctx)
+	default:
+		return ctx
+	}
+}
+
+// Enable returns a new context with the flag enabled and logs the override.
+func Enable(t testing.TB, ctx context.Context, key feature.BoolKey) context.Context {
+	t.Helper()
+
+	return Set[bool](t, ctx, key, true)
+}
+
+// Disable returns a new context with the flag explicitly disabled and logs the override.
+func Disable(t testing.TB, ctx context.Context, key feature.BoolKey) context.Context {
+	t.Helper()
+
+	return Set[bool](t, ctx, key, false)
+}
+
+// Set returns a new context with the value associated with the key and logs the override.
+// If the value is rejected by a validator of the key, the test fails immediately.
+func Set[V any](t testing.TB, ctx context.Context, key feature.Key[V], value V) context.Context {
+	t.Helper()
+
+	ctx, err := key.TryWithValue(ctx, value)
+	if err != nil {
+		t.Fatalf("featuretest: %v", err)
+	}
+
+	t.Logf("featuretest: %s = %v", key, value)
+
+	return ctx
+}
+
+// Matrix runs fn as a subtest for every combination of the states of the flags:
+// not set, enabled and explicitly disabled. The context passed to fn is derived from
+// context.Background() with the flags in the states of the combination.
+//
+// Subtests are named after the flags and their states, e.g. "new-ui=enabled,beta=unset",
+// and run in the order unset, enabled, disabled with the first flag varying slowest.
+// With n flags, fn runs 3^n times.
+func Matrix(t *testing.T, fn func(t *testing.T, ctx context.Context), keys ...feature.BoolKey) {
+	t.Helper()
+
+	states := make([]State, len(keys))
+
+	for {
+		ctx := context.Background()
+		names := make([]string, 0, len(keys))
+
+		for idx, key := range keys {
+			ctx = states[idx].Apply(ctx, key)
+			names = append(names, key.String()+"="+states[idx].String())
+		}
+
+		t.Run(strings.Join(names, ","), func(t *testing.T) {
+			fn(t, ctx)
+		})
+
+		if !next(states) {
+			return
+		}
+	}
+}
+
+// next advances states to the next combination like an odometer, with the last flag
+// varying fastest. It returns false when all combinations have been visited.
+func next(states []State) bool {
+	for idx := len(states) - 1; idx >= 0; idx-- {
+		if states[idx] < Disabled {
+			states[idx]++
+
+			return true
+		}
+
+		states[idx] = Unset
+	}
+
+	return false
+}
+
+// Override forces the value of the key process-wide with feature.Override for the rest of the
+// test and logs the override. The override is restored when the test and its subtests complete.
+// If the value is rejected by a validator of the key, the test fails immediately.
+//
+// Since the override affects every context in the process, tests using it must not run
+// in parallel with tests that read the key.
+func Override[V any](t testing.TB, key feature.Key[V], value V, options ...feature.OverrideOption) {
+	t.Helper()
+
+	if _, err := key.TryWithValue(context.Background(), value); err != nil {
+		t.Fatalf("featuretest: %v", err)
+	}
+
+	restore := feature.Override(key, value, options...)
+	t.Cleanup(restore)
+
+	t.Logf("featuretest: %s overridden to %v", key, value)
+}
@@ -0,0 +1,133 @@
+package featuretest_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mpyw/feature"
+	"github.com/mpyw/feature/featuretest"
+)
+
+// TestSet tests the helpers deriving contexts with overridden values.
+func TestSet(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Enable and Disable", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamedBool("new-ui")
+
+		ctx := featuretest.Enable(t, context.Background(), key)
+		if !key.Enabled(ctx) {
+			t.Error("Enabled() = false, want true")
+		}
+
+		ctx = featuretest.Disable(t, ctx, key)
+		if !key.ExplicitlyDisabled(ctx) {
+			t.Error("ExplicitlyDisabled() = false, want true")
+		}
+	})
+
+	t.Run("Set", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-items")
+
+		ctx := featuretest.Set(t, context.Background(), key, 10)
+		if got := key.Get(ctx); got != 10 {
+			t.Errorf("Get() = %d, want 10", got)
+		}
+	})
+}
+
+// TestState tests the states run by Matrix.
+func TestState(t *testing.T) {
+	t.Parallel()
+
+	key := feature.NewNamedBool("new-ui")
+
+	tests := []struct {
+		state   featuretest.State
+		name    string
+		isSet   bool
+		enabled bool
+	}{
+		{state: featuretest.Unset, name: "unset", isSet: false, enabled: false},
+		{state: featuretest.Enabled, name: "enabled", isSet: true, enabled: true},
+		{state: featuretest.Disabled, name: "disabled", isSet: true, enabled: false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.state.String(); got != tt.name {
+			t.Errorf("String() = %q, want %q", got, tt.name)
+		}
+
+		ctx := tt.state.Apply(context.Background(), key)
+		if key.IsSet(ctx) != tt.isSet || key.Enabled(ctx) != tt.enabled {
+			t.Errorf("Apply(%s): IsSet(), Enabled() = %v, %v, want %v, %v",
+				tt.state, key.IsSet(ctx), key.Enabled(ctx), tt.isSet, tt.enabled)
+		}
+	}
+}
+
+// TestMatrix tests running subtests for every combination of flag states.
+func TestMatrix(t *testing.T) {
+	t.Parallel()
+
+	newUI := feature.NewNamedBool("new-ui")
+	beta := feature.NewNamedBool("beta")
+
+	var names []string
+
+	featuretest.Matrix(t, func(t *testing.T, ctx context.Context) {
+		name := t.Name()[strings.LastIndex(t.Name(), "/")+1:]
+		names = append(names, name)
+
+		want := fmt.Sprintf("new-ui=%s,beta=%s", state(ctx, newUI), state(ctx, beta))
+		if name != want {
+			t.Errorf("subtest %s ran with %s", name, want)
+		}
+	}, newUI, beta)
+
+	want := []string{
+		"new-ui=unset,beta=unset", "new-ui=unset,beta=enabled", "new-ui=unset,beta=disabled",
+		"new-ui=enabled,beta=unset", "new-ui=enabled,beta=enabled", "new-ui=enabled,beta=disabled",
+		"new-ui=disabled,beta=unset", "new-ui=disabled,beta=enabled", "new-ui=disabled,beta=disabled",
+	}
+	if strings.Join(names, " ") != strings.Join(want, " ") {
+		t.Errorf("subtests = %v, want %v", names, want)
+	}
+}
+
+// state returns the state of the key in the context.
+func state(ctx context.Context, key feature.BoolKey) featuretest.State {
+	switch {
+	case key.IsNotSet(ctx):
+		return featuretest.Unset
+	case key.Enabled(ctx):
+		return featuretest.Enabled
+	default:
+		return featuretest.Disabled
+	}
+}
+
+// TestOverride tests overriding values for the duration of a test.
+func TestOverride(t *testing.T) {
+	t.Parallel()
+
+	key := feature.NewNamedBool("kill-switch")
+
+	t.Run("override", func(t *testing.T) {
+		featuretest.Override(t, key, true)
+
+		if !key.Enabled(context.Background()) {
+			t.Error("Enabled() = false, want true")
+		}
+	})
+
+	if key.IsSet(context.Background()) {
+		t.Error("IsSet() = true, want override to be restored after the subtest")
+	}
+}
@@ -0,0 +1,280 @@
+package feature
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultPollInterval is the interval at which WatchFile checks the file for changes
+// unless WithPollInterval is given.
+const DefaultPollInterval = time.Second
+
+// fileValue is a decoded value bound to the key it belongs to.
+type fileValue struct {
+	entry Entry
+	value any
+}
+
+// fileValues is the set of values decoded from a file.
+type fileValues []fileValue
+
+// apply returns a new context with all values applied in a single context node.
+func (vs fileValues) apply(ctx context.Context) context.Context {
+	bindings := make([]Binding, 0, len(vs))
+	for _, v := range vs {
+		bindings = append(bindings, v.entry.Key.bindAny(v.value))
+	}
+
+	return withBindings(ctx, bindings)
+}
+
+// LoadFile returns a new context with values read from a JSON file.
+//
+// The file must contain a JSON object whose members are keyed by key name, e.g.
+//
+//	{"new-ui": true, "max-items": 100, "timeout": 5000000000}
+//
+// Each member is resolved against the named keys recorded in the Registry and decoded
+// with encoding/json into the key's value type, so values are type-checked.
+// Keys that do not appear in the file are left untouched.
+//
+// If some members cannot be resolved or decoded, LoadFile still returns a context
+// containing the values that could, together with an error joining an *UnknownKeyError
+// or *ParseError for each offending member.
+func (r *Registry) LoadFile(ctx context.Context, path string) (context.Context, error) {
+	values, err := r.readFile(path)
+
+	return values.apply(ctx), err
+}
+
+// readFile reads and decodes a JSON file.
+func (r *Registry) readFile(path string) (fileValues, error) {
+	data, err := os.ReadFile(path) //#nosec G304 -- reading a user-specified file is the purpose
+	if err != nil {
+		return nil, fmt.Errorf("reading feature file: %w", err)
+	}
+
+	return r.decodeFile(path, data)
+}
+
+// decodeFile decodes the contents of a JSON file.
+// Values are returned in registration order of their keys.
+func (r *Registry) decodeFile(path string, data []byte) (fileValues, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, fmt.Errorf("decoding feature file %s: %w", path, err)
+	}
+
+	var errs []error
+
+	for _, name := range sortedNames(members) {
+		if _, ok := r.lookupNamed(name); !ok {
+			errs = append(errs, fmt.Errorf("feature file %s: %w", path, &UnknownKeyError{Name: name}))
+		}
+	}
+
+	var values fileValues
+
+	for _, entry := range r.snapshot() {
+		raw, ok := members[entry.Name]
+		if !ok || entry.Anonymous {
+			continue
+		}
+
+		value, err := entry.Key.decodeAny(raw)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("feature file %s: %w", path, err))
+
+			continue
+		}
+
+		values = append(values, fileValue{entry: entry, value: value})
+	}
+
+	return values, errors.Join(errs...)
+}
+
+// sortedNames returns the member names in sorted order for deterministic error reporting.
+func sortedNames(members map[string]json.RawMessage) []string {
+	names := make([]string, 0, len(members))
+	for name := range members {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// WatchOption is a function that configures the behavior of a FileWatcher.
+type WatchOption func(*watchOptions)
+
+// watchOptions configures the behavior of a FileWatcher.
+type watchOptions struct {
+	interval time.Duration
+	onError  func(error)
+}
+
+// WithPollInterval returns an option that sets how often the file is checked for changes.
+// The interval must be positive; otherwise WatchFile returns an error.
+func WithPollInterval(interval time.Duration) WatchOption {
+	return func(o *watchOptions) {
+		o.interval = interval
+	}
+}
+
+// WithReloadErrorHandler returns an option that sets a callback invoked when reloading
+// the file fails. The previously loaded values stay in effect.
+func WithReloadErrorHandler(onError func(err error)) WatchOption {
+	return func(o *watchOptions) {
+		o.onError = onError
+	}
+}
+
+// FileWatcher keeps the values of a JSON file up to date by polling it for changes.
+//
+// A FileWatcher is safe for concurrent use.
+type FileWatcher struct {
+	registry *Registry
+	path     string
+	opts     *watchOptions
+	current  atomic.Pointer[fileValues]
+	stop     chan struct{}
+	done     chan struct{}
+	once     sync.Once
+}
+
+// WatchFile loads a JSON file like LoadFile and keeps polling it for changes,
+// so that long-running servers pick up new values without restarting.
+//
+// Unlike LoadFile, a file is only accepted as a whole: if the initial load fails,
+// WatchFile returns the error, and if a reload fails, the previously loaded values
+// stay in effect and the error is reported to the handler given with
+// WithReloadErrorHandler.
+//
+// Call Apply to derive contexts from the latest loaded values and Close to stop watching.
+//
+// Example:
+//
+//	watcher, err := registry.WatchFile("/etc/app/features.json",
+//	    feature.WithReloadErrorHandler(func(err error) { log.Println(err) }),
+//	)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer watcher.Close()
+//
+//	handler := func(w http.ResponseWriter, r *http.Request) {
+//	    ctx := watcher.Apply(r.Context())
+//	    // ...
+//	}
+func (r *Registry) WatchFile(path string, options ...WatchOption) (*FileWatcher, error) {
+	opts := &watchOptions{
+		interval: DefaultPollInterval,
+		onError:  nil,
+	}
+	for _, optFn := range options {
+		optFn(opts)
+	}
+
+	if opts.interval <= 0 {
+		return nil, fmt.Errorf("watching feature file: non-positive poll interval %s", opts.interval)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading feature file: %w", err)
+	}
+
+	values, err := r.readFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	watcher := &FileWatcher{
+		registry: r,
+		path:     path,
+		opts:     opts,
+		current:  atomic.Pointer[fileValues]{},
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		once:     sync.Once{},
+	}
+	watcher.current.Store(&values)
+
+	go watcher.poll(info)
+
+	return watcher, nil
+}
+
+// Apply returns a new context with the latest loaded values applied.
+func (w *FileWatcher) Apply(ctx context.Context) context.Context {
+	return w.current.Load().apply(ctx)
+}
+
+// Close stops watching the file. The latest loaded values remain available through Apply.
+// Close always returns nil.
+func (w *FileWatcher) Close() error {
+	w.once.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+
+	return nil
+}
+
+// poll checks the file for changes until Close is called.
+func (w *FileWatcher) poll(last os.FileInfo) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.opts.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			last = w.reload(last)
+		}
+	}
+}
+
+// reload reloads the file if it has changed since last and returns the latest file info.
+func (w *FileWatcher) reload(last os.FileInfo) os.FileInfo {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		w.reportError(fmt.Errorf("reading feature file: %w", err))
+
+		return last
+	}
+
+	if info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+		return last
+	}
+
+	values, err := w.registry.readFile(w.path)
+	if err != nil {
+		w.reportError(err)
+
+		return info
+	}
+
+	w.current.Store(&values)
+
+	return info
+}
+
+// reportError passes the error to the handler, if any.
+func (w *FileWatcher) reportError(err error) {
+	if w.opts.onError != nil {
+		w.opts.onError(err)
+	}
+}
@@ -0,0 +1,315 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mpyw/feature"
+)
+
+// writeFeatureFile writes the contents to the path and bumps its modification time
+// so that changes are detected even on file systems with coarse timestamps.
+func writeFeatureFile(t *testing.T, path, contents string, modTime time.Time) {
+	t.Helper()
+
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Chtimes() error = %v", err)
+	}
+}
+
+// waitFor polls cond until it returns true or the deadline expires.
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition was not met before the deadline")
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestLoadFile tests loading values from a JSON file.
+func TestLoadFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes values by key name", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+		tags := feature.NewNamed[[]string]("tags", feature.WithRegistry(registry))
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+		path := filepath.Join(t.TempDir(), "features.json")
+		writeFeatureFile(t, path, `{"new-ui": false, "max-items": 100, "tags": ["a", "b"]}`, time.Now())
+
+		ctx, err := registry.LoadFile(context.Background(), path)
+		if err != nil {
+			t.Fatalf("LoadFile() error = %v", err)
+		}
+
+		if !newUI.ExplicitlyDisabled(ctx) {
+			t.Error("newUI.ExplicitlyDisabled() = false, want true")
+		}
+
+		if got := maxItems.Get(ctx); got != 100 {
+			t.Errorf("maxItems.Get() = %d, want 100", got)
+		}
+
+		if got := tags.Get(ctx); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+			t.Errorf("tags.Get() = %v, want [a b]", got)
+		}
+
+		if region.IsSet(ctx) {
+			t.Error("region.IsSet() = true, want false for missing member")
+		}
+	})
+
+	t.Run("type mismatches and unknown names are reported", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+		path := filepath.Join(t.TempDir(), "features.json")
+		writeFeatureFile(t, path, `{"max-items": "many", "region": "eu", "unknown": 1}`, time.Now())
+
+		ctx, err := registry.LoadFile(context.Background(), path)
+		if err == nil {
+			t.Fatal("LoadFile() error = nil, want error")
+		}
+
+		var parseErr *feature.ParseError
+		if !errors.As(err, &parseErr) || parseErr.Key != "max-items" {
+			t.Errorf("errors.As(*feature.ParseError) = %v, want error for max-items", parseErr)
+		}
+
+		var unknownErr *feature.UnknownKeyError
+		if !errors.As(err, &unknownErr) || unknownErr.Name != "unknown" {
+			t.Errorf("errors.As(*feature.UnknownKeyError) = %v, want error for unknown", unknownErr)
+		}
+
+		if maxItems.IsSet(ctx) {
+			t.Error("maxItems.IsSet() = true, want invalid value to be skipped")
+		}
+
+		if got := region.Get(ctx); got != "eu" {
+			t.Errorf("region.Get() = %q, want valid values to be applied", got)
+		}
+	})
+
+	t.Run("anonymous keys cannot be addressed", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		anonymous := feature.New[int](feature.WithRegistry(registry))
+
+		path := filepath.Join(t.TempDir(), "features.json")
+		writeFeatureFile(t, path, fmt.Sprintf(`{%q: 1}`, anonymous.String()), time.Now())
+
+		ctx, err := registry.LoadFile(context.Background(), path)
+
+		var unknownErr *feature.UnknownKeyError
+		if !errors.As(err, &unknownErr) {
+			t.Errorf("LoadFile() error = %v, want *feature.UnknownKeyError", err)
+		}
+
+		if anonymous.IsSet(ctx) {
+			t.Error("IsSet() = true, want false for anonymous key")
+		}
+	})
+
+	t.Run("invalid JSON and missing files are reported", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		dir := t.TempDir()
+
+		path := filepath.Join(dir, "features.json")
+		writeFeatureFile(t, path, `[1, 2]`, time.Now())
+
+		if _, err := registry.LoadFile(context.Background(), path); err == nil {
+			t.Error("LoadFile() error = nil, want error for non-object JSON")
+		}
+
+		_, err := registry.LoadFile(context.Background(), filepath.Join(dir, "missing.json"))
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("LoadFile() error = %v, want %v", err, os.ErrNotExist)
+		}
+	})
+}
+
+// TestWatchFile tests reloading values when a JSON file changes.
+func TestWatchFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("picks up changes", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		path := filepath.Join(t.TempDir(), "features.json")
+		now := time.Now()
+		writeFeatureFile(t, path, `{"max-items": 100}`, now)
+
+		watcher, err := registry.WatchFile(path, feature.WithPollInterval(time.Millisecond))
+		if err != nil {
+			t.Fatalf("WatchFile() error = %v", err)
+		}
+
+		t.Cleanup(func() { _ = watcher.Close() })
+
+		if got := maxItems.Get(watcher.Apply(context.Background())); got != 100 {
+			t.Errorf("Get() = %d, want 100", got)
+		}
+
+		writeFeatureFile(t, path, `{"max-items": 200}`, now.Add(time.Second))
+
+		waitFor(t, func() bool {
+			return maxItems.Get(watcher.Apply(context.Background())) == 200
+		})
+	})
+
+	t.Run("reload errors keep previous values", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		path := filepath.Join(t.TempDir(), "features.json")
+		now := time.Now()
+		writeFeatureFile(t, path, `{"max-items": 100}`, now)
+
+		var (
+			mu         sync.Mutex
+			reloadErrs []error
+		)
+
+		watcher, err := registry.WatchFile(path,
+			feature.WithPollInterval(time.Millisecond),
+			feature.WithReloadErrorHandler(func(err error) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				reloadErrs = append(reloadErrs, err)
+			}),
+		)
+		if err != nil {
+			t.Fatalf("WatchFile() error = %v", err)
+		}
+
+		t.Cleanup(func() { _ = watcher.Close() })
+
+		writeFeatureFile(t, path, `{"max-items": "many"}`, now.Add(time.Second))
+
+		waitFor(t, func() bool {
+			mu.Lock()
+			defer mu.Unlock()
+
+			return len(reloadErrs) > 0
+		})
+
+		if got := maxItems.Get(watcher.Apply(context.Background())); got != 100 {
+			t.Errorf("Get() = %d, want previous value 100", got)
+		}
+	})
+
+	t.Run("initial load errors are returned", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		path := filepath.Join(t.TempDir(), "features.json")
+		writeFeatureFile(t, path, `{"max-items": "many"}`, time.Now())
+
+		if _, err := registry.WatchFile(path); err == nil {
+			t.Error("WatchFile() error = nil, want error")
+		}
+
+		if _, err := registry.WatchFile(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+			t.Error("WatchFile() error = nil, want error for missing file")
+		}
+	})
+
+	t.Run("non-positive poll intervals are rejected", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		path := filepath.Join(t.TempDir(), "features.json")
+		writeFeatureFile(t, path, `{}`, time.Now())
+
+		for _, interval := range []time.Duration{0, -time.Second} {
+			watcher, err := registry.WatchFile(path, feature.WithPollInterval(interval))
+			if err == nil {
+				_ = watcher.Close()
+
+				t.Errorf("WatchFile(WithPollInterval(%s)) error = nil, want error", interval)
+			}
+		}
+	})
+
+	t.Run("Close is idempotent", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		path := filepath.Join(t.TempDir(), "features.json")
+		writeFeatureFile(t, path, `{}`, time.Now())
+
+		watcher, err := registry.WatchFile(path)
+		if err != nil {
+			t.Fatalf("WatchFile() error = %v", err)
+		}
+
+		if err := watcher.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+
+		if err := watcher.Close(); err != nil {
+			t.Errorf("second Close() error = %v", err)
+		}
+	})
+}
+
+func ExampleRegistry_LoadFile() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+	)
+
+	dir, _ := os.MkdirTemp("", "feature")
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "features.json")
+	_ = os.WriteFile(path, []byte(`{"new-ui": true, "max-items": 100}`), 0o600)
+
+	ctx, err := registry.LoadFile(context.Background(), path)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Println(EnableNewUI.Inspect(ctx))
+	fmt.Println(MaxItems.Inspect(ctx))
+
+	// Output:
+	// new-ui: true
+	// max-items: 100
+}
@@ -0,0 +1,111 @@
+package feature
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// FlagPrefix is prepended to key names to form command-line flag names in BindFlags.
+const FlagPrefix = "feature."
+
+// Flags holds the command-line flags bound to keys by BindFlags.
+type Flags struct {
+	values []*flagValue
+}
+
+// flagValue is the flag.Value bound to a single key.
+type flagValue struct {
+	entry Entry
+	text  string
+	value any
+	set   bool
+}
+
+// BindFlags defines a command-line flag in fs for every named key recorded in the Registry.
+//
+// The flag name is FlagPrefix followed by the key name, e.g. "-feature.max-items".
+// Values are parsed according to the key's value type, like LoadEnv does.
+// Flags of bool keys can be given without a value: "-feature.new-ui" enables the key and
+// "-feature.new-ui=false" explicitly disables it. Keys whose flag is not given on the
+// command line stay unset, preserving the distinction between unset and ExplicitlyDisabled.
+//
+// Keys whose flag name is already defined in fs are skipped, so that if several keys share a name,
+// the first registered one is bound, as with Registry.Lookup. Keys whose names contain "=",
+// which the flag package does not accept, are skipped as well.
+//
+// After fs.Parse, call Apply on the result to obtain a context with the parsed values.
+//
+// Example:
+//
+//	flags := feature.BindFlags(flag.CommandLine, registry)
+//	flag.Parse()
+//	ctx := flags.Apply(context.Background())
+func BindFlags(fs *flag.FlagSet, registry *Registry) *Flags {
+	flags := &Flags{values: nil}
+
+	for _, entry := range registry.snapshot() {
+		// The flag package rejects names containing "="; FlagPrefix rules out a leading "-".
+		if entry.Anonymous || strings.Contains(entry.Name, "=") {
+			continue
+		}
+
+		name := FlagPrefix + entry.Name
+		if fs.Lookup(name) != nil {
+			continue
+		}
+
+		value := &flagValue{entry: entry, text: "", value: nil, set: false}
+		fs.Var(value, name, fmt.Sprintf("set feature flag %s (%s)", entry.Name, entry.Type))
+		flags.values = append(flags.values, value)
+	}
+
+	return flags
+}
+
+// Apply returns a new context with the values of the flags given on the command line.
+// Keys whose flag was not given are left untouched.
+func (f *Flags) Apply(ctx context.Context) context.Context {
+	var bindings []Binding
+
+	for _, value := range f.values {
+		if value.set {
+			bindings = append(bindings, value.entry.Key.bindAny(value.value))
+		}
+	}
+
+	return withBindings(ctx, bindings)
+}
+
+// String returns the text given on the command line.
+// This implements flag.Value.
+func (v *flagValue) String() string {
+	if v == nil {
+		return ""
+	}
+
+	return v.text
+}
+
+// Set parses the text given on the command line.
+// This implements flag.Value.
+func (v *flagValue) Set(text string) error {
+	value, err := v.entry.Key.parseAny(text)
+	if err != nil {
+		return err
+	}
+
+	v.text = text
+	v.value = value
+	v.set = true
+
+	return nil
+}
+
+// IsBoolFlag reports whether the flag can be given without a value.
+// This implements the optional boolFlag interface of the flag package.
+func (v *flagValue) IsBoolFlag() bool {
+	return v.entry.Type.Kind() == reflect.Bool
+}
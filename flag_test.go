@@ -0,0 +1,221 @@
+package feature_test
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mpyw/feature"
+)
+
+// TestBindFlags tests binding keys to command-line flags.
+func TestBindFlags(t *testing.T) {
+	t.Parallel()
+
+	newFlagSet := func() *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+
+		return fs
+	}
+
+	t.Run("parses typed values", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+		timeout := feature.NewNamed[time.Duration]("timeout", feature.WithRegistry(registry))
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+		fs := newFlagSet()
+		flags := feature.BindFlags(fs, registry)
+
+		err := fs.Parse([]string{"-feature.max-items=100", "-feature.timeout", "5s", "--feature.region=eu"})
+		if err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+
+		ctx := flags.Apply(context.Background())
+
+		if got := maxItems.Get(ctx); got != 100 {
+			t.Errorf("maxItems.Get() = %d, want 100", got)
+		}
+
+		if got := timeout.Get(ctx); got != 5*time.Second {
+			t.Errorf("timeout.Get() = %v, want 5s", got)
+		}
+
+		if got := region.Get(ctx); got != "eu" {
+			t.Errorf("region.Get() = %q, want %q", got, "eu")
+		}
+	})
+
+	t.Run("bool keys support three states", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		enabled := feature.NewNamedBool("enabled", feature.WithRegistry(registry))
+		disabled := feature.NewNamedBool("disabled", feature.WithRegistry(registry))
+		unset := feature.NewNamedBool("unset", feature.WithRegistry(registry))
+
+		fs := newFlagSet()
+		flags := feature.BindFlags(fs, registry)
+
+		if err := fs.Parse([]string{"-feature.enabled", "-feature.disabled=false"}); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+
+		ctx := flags.Apply(context.Background())
+
+		if !enabled.Enabled(ctx) {
+			t.Error("enabled.Enabled() = false, want true")
+		}
+
+		if !disabled.ExplicitlyDisabled(ctx) {
+			t.Error("disabled.ExplicitlyDisabled() = false, want true")
+		}
+
+		if unset.IsSet(ctx) {
+			t.Error("unset.IsSet() = true, want false when the flag is not given")
+		}
+	})
+
+	t.Run("invalid values are rejected by Parse", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		fs := newFlagSet()
+		_ = feature.BindFlags(fs, registry)
+
+		err := fs.Parse([]string{"-feature.max-items=many"})
+		if err == nil {
+			t.Fatal("Parse() error = nil, want error")
+		}
+
+		checkContains(t, err.Error(), "-feature.max-items")
+		checkContains(t, err.Error(), `parsing "many" for key max-items`)
+	})
+
+	t.Run("anonymous keys are not bound", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewBool(feature.WithRegistry(registry))
+		_ = feature.NewNamedBool("named", feature.WithRegistry(registry))
+
+		fs := newFlagSet()
+		_ = feature.BindFlags(fs, registry)
+
+		var names []string
+
+		fs.VisitAll(func(f *flag.Flag) {
+			names = append(names, f.Name)
+		})
+
+		if len(names) != 1 || names[0] != "feature.named" {
+			t.Errorf("defined flags = %v, want [feature.named]", names)
+		}
+	})
+
+	t.Run("duplicate names bind the first key", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		first := feature.NewNamed[int]("max", feature.WithRegistry(registry))
+		second := feature.NewNamed[int]("max", feature.WithRegistry(registry))
+
+		fs := newFlagSet()
+		flags := feature.BindFlags(fs, registry)
+
+		if err := fs.Parse([]string{"-feature.max=3"}); err != nil {
+			t.Fatalf("Parse() error = %v", err)
+		}
+
+		ctx := flags.Apply(context.Background())
+
+		if got := first.Get(ctx); got != 3 {
+			t.Errorf("first.Get() = %d, want 3", got)
+		}
+
+		if second.IsSet(ctx) {
+			t.Error("second.IsSet() = true, want false")
+		}
+	})
+
+	t.Run("invalid flag names are not bound", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamedBool("a=b", feature.WithRegistry(registry))
+		_ = feature.NewNamedBool("-dash", feature.WithRegistry(registry))
+		_ = feature.NewNamedBool("valid", feature.WithRegistry(registry))
+
+		fs := newFlagSet()
+		_ = feature.BindFlags(fs, registry)
+
+		var names []string
+
+		fs.VisitAll(func(f *flag.Flag) {
+			names = append(names, f.Name)
+		})
+
+		if want := []string{"feature.-dash", "feature.valid"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("defined flags = %v, want %v", names, want)
+		}
+	})
+
+	t.Run("usage describes the key", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		fs := newFlagSet()
+		_ = feature.BindFlags(fs, registry)
+
+		f := fs.Lookup("feature.max-items")
+		if f == nil {
+			t.Fatal("Lookup() = nil, want flag")
+		}
+
+		if want := "set feature flag max-items (int)"; f.Usage != want {
+			t.Errorf("Usage = %q, want %q", f.Usage, want)
+		}
+
+		if f.DefValue != "" {
+			t.Errorf("DefValue = %q, want empty", f.DefValue)
+		}
+	})
+}
+
+func ExampleBindFlags() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		EnableBeta  = feature.NewNamedBool("beta", feature.WithRegistry(registry))
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+	)
+
+	fs := flag.NewFlagSet("server", flag.ExitOnError)
+	flags := feature.BindFlags(fs, registry)
+
+	_ = fs.Parse([]string{"-feature.new-ui", "-feature.max-items=100"})
+
+	ctx := flags.Apply(context.Background())
+
+	fmt.Println(EnableNewUI.Inspect(ctx))
+	fmt.Println(EnableBeta.Inspect(ctx))
+	fmt.Println(MaxItems.Inspect(ctx))
+
+	// Output:
+	// new-ui: true
+	// beta: <not set>
+	// max-items: 100
+}
@@ -5,12 +5,22 @@ import (
 	"reflect"
 )
 
+// typeOf returns the reflect.Type of V, including interface types.
+func typeOf[V any]() reflect.Type {
+	return reflect.TypeOf((*V)(nil)).Elem()
+}
+
 // GoString returns a Go syntax representation of the key.
 // The output is a valid Go expression that creates an equivalent key
 // (though with a different identity).
 // This implements fmt.GoStringer.
 func (k key[V]) GoString() string {
-	typeName := reflect.TypeOf((*V)(nil)).Elem().String()
+	typeName := typeOf[V]().String()
+
+	if k.hasDefault {
+		return fmt.Sprintf("feature.New[%s](feature.WithName(%q), feature.WithDefault[%s](%#v))",
+			typeName, k.name, typeName, k.defaultValue)
+	}
 
 	return fmt.Sprintf("feature.New[%s](feature.WithName(%q))", typeName, k.name)
 }
@@ -20,5 +30,24 @@ func (k key[V]) GoString() string {
 // (though with a different identity).
 // This implements fmt.GoStringer.
 func (k boolKey) GoString() string {
+	if k.hasDefault {
+		return fmt.Sprintf("feature.NewBool(feature.WithName(%q), feature.WithDefault(%t))", k.name, k.defaultValue)
+	}
+
 	return fmt.Sprintf("feature.NewBool(feature.WithName(%q))", k.name)
 }
+
+// GoString returns a Go syntax representation of the enum key.
+// The output is a valid Go expression that creates an equivalent key
+// (though with a different identity).
+// This implements fmt.GoStringer.
+func (k enumKey[V]) GoString() string {
+	typeName := typeOf[V]().String()
+
+	if k.hasDefault {
+		return fmt.Sprintf("feature.NewEnum[%s](%q, %#v, feature.WithDefault[%s](%#v))",
+			typeName, k.name, k.variants, typeName, k.defaultValue)
+	}
+
+	return fmt.Sprintf("feature.NewEnum[%s](%q, %#v)", typeName, k.name, k.variants)
+}
@@ -47,6 +47,34 @@ func TestGoString(t *testing.T) {
 		assertCompilesWithFeatureImport(t, goStr)
 	})
 
+	t.Run("Key GoString includes default value", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[string]("region", feature.WithDefault("eu"))
+		goStr := key.GoString()
+
+		want := `feature.New[string](feature.WithName("region"), feature.WithDefault[string]("eu"))`
+		if goStr != want {
+			t.Errorf("GoString() = %q, want %q", goStr, want)
+		}
+
+		assertCompilesWithFeatureImport(t, goStr)
+	})
+
+	t.Run("BoolKey GoString includes default value", func(t *testing.T) {
+		t.Parallel()
+
+		flag := feature.NewNamedBool("new-ui", feature.WithDefault(true))
+		goStr := flag.GoString()
+
+		want := `feature.NewBool(feature.WithName("new-ui"), feature.WithDefault(true))`
+		if goStr != want {
+			t.Errorf("GoString() = %q, want %q", goStr, want)
+		}
+
+		assertCompilesWithFeatureImport(t, goStr)
+	})
+
 	t.Run("BoolKey GoString returns valid Go expression", func(t *testing.T) {
 		t.Parallel()
 
@@ -60,6 +88,20 @@ func TestGoString(t *testing.T) {
 
 		assertCompilesWithFeatureImport(t, goStr)
 	})
+
+	t.Run("EnumKey GoString includes variants and default value", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewEnum("algorithm", []string{"v1", "v2"}, feature.WithDefault("v1"))
+		goStr := key.GoString()
+
+		want := `feature.NewEnum[string]("algorithm", []string{"v1", "v2"}, feature.WithDefault[string]("v1"))`
+		if goStr != want {
+			t.Errorf("GoString() = %q, want %q", goStr, want)
+		}
+
+		assertCompilesWithFeatureImport(t, goStr)
+	})
 }
 
 // assertCompilesWithFeatureImport verifies that the given expression compiles
@@ -0,0 +1,216 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultHeaderName is the HTTP header used to propagate feature values
+// unless WithHeaderName is given.
+const DefaultHeaderName = "X-Feature-Flags"
+
+// ErrMalformedHeader is returned when a header value does not follow the
+// "<name>=<value>,<name>=<value>" format.
+var ErrMalformedHeader = errors.New("malformed feature header")
+
+// HeaderOption is a function that configures the behavior of the HTTP middleware and transport.
+type HeaderOption func(*headerOptions)
+
+// headerOptions configures the behavior of the HTTP middleware and transport.
+type headerOptions struct {
+	name    string
+	onError func(w http.ResponseWriter, r *http.Request, err error) bool
+}
+
+// WithHeaderName returns an option that sets the HTTP header used to propagate feature values.
+func WithHeaderName(name string) HeaderOption {
+	return func(o *headerOptions) {
+		o.name = name
+	}
+}
+
+// WithHeaderErrorHandler returns an option that sets the policy for incoming headers that
+// cannot be fully decoded, e.g. because they contain unknown names or invalid values.
+//
+// The handler receives the error and returns whether the request should still be served
+// with the values that could be decoded. To reject the request, write a response and return false.
+// Without this option, such errors are ignored.
+//
+// Example:
+//
+//	feature.WithHeaderErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) bool {
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	    return false
+//	})
+func WithHeaderErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error) bool) HeaderOption {
+	return func(o *headerOptions) {
+		o.onError = handler
+	}
+}
+
+// headerOptionsFrom applies the given option functions to create a configured headerOptions.
+func headerOptionsFrom(options []HeaderOption) *headerOptions {
+	opts := &headerOptions{
+		name:    DefaultHeaderName,
+		onError: nil,
+	}
+	for _, optFn := range options {
+		optFn(opts)
+	}
+
+	return opts
+}
+
+// EncodeHeader encodes the propagatable keys set in the context into a header value
+// such as "new-ui=true,max-items=100".
+//
+// Only keys created with WithPropagation are encoded. Names and values are percent-encoded
+// so that they never contain the separators. It returns an empty string if no propagatable
+// key is set.
+func (r *Registry) EncodeHeader(ctx context.Context) (string, error) {
+	values, err := r.encodePropagated(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	members := make([]string, 0, len(values))
+	for _, v := range values {
+		members = append(members, url.QueryEscape(v.name)+"="+url.QueryEscape(v.text))
+	}
+
+	return strings.Join(members, ","), nil
+}
+
+// DecodeHeader returns a new context with the values decoded from a header value
+// produced by EncodeHeader.
+//
+// Names are resolved against the propagatable keys recorded in the Registry.
+// If some members cannot be decoded, DecodeHeader still returns a context containing the
+// values that could, together with an error joining an *UnknownKeyError, *ParseError,
+// *ValidationError or ErrMalformedHeader for each offending member.
+func (r *Registry) DecodeHeader(ctx context.Context, header string) (context.Context, error) {
+	var (
+		bindings []Binding
+		errs     []error
+	)
+
+	for _, member := range strings.Split(header, ",") {
+		member = strings.TrimSpace(member)
+		if member == "" {
+			continue
+		}
+
+		binding, err := r.decodeHeaderMember(member)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		bindings = append(bindings, binding)
+	}
+
+	return withBindings(ctx, bindings), errors.Join(errs...)
+}
+
+// decodeHeaderMember decodes a single "<name>=<value>" member.
+func (r *Registry) decodeHeaderMember(member string) (Binding, error) {
+	var zero Binding
+
+	rawName, rawText, ok := strings.Cut(member, "=")
+	if !ok {
+		return zero, fmt.Errorf("%w: missing '=' in %q", ErrMalformedHeader, member)
+	}
+
+	name, err := url.QueryUnescape(strings.TrimSpace(rawName))
+	if err != nil {
+		return zero, fmt.Errorf("%w: %w", ErrMalformedHeader, err)
+	}
+
+	text, err := url.QueryUnescape(strings.TrimSpace(rawText))
+	if err != nil {
+		return zero, fmt.Errorf("%w: %w", ErrMalformedHeader, err)
+	}
+
+	return r.decodePropagated(name, text)
+}
+
+// Middleware returns HTTP middleware that decodes the feature header of incoming requests
+// into the request context using the propagatable keys recorded in the Registry.
+//
+// Example:
+//
+//	mux := http.NewServeMux()
+//	http.ListenAndServe(":8080", registry.Middleware()(mux))
+func (r *Registry) Middleware(options ...HeaderOption) func(http.Handler) http.Handler {
+	opts := headerOptionsFrom(options)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			header := req.Header.Get(opts.name)
+			if header == "" {
+				next.ServeHTTP(w, req)
+
+				return
+			}
+
+			ctx, err := r.DecodeHeader(req.Context(), header)
+			if err != nil && opts.onError != nil && !opts.onError(w, req, err) {
+				return
+			}
+
+			next.ServeHTTP(w, req.WithContext(ctx))
+		})
+	}
+}
+
+// Transport returns an http.RoundTripper that encodes the propagatable keys set in the
+// request context into the feature header of outgoing requests, then delegates to base.
+// If base is nil, http.DefaultTransport is used.
+//
+// Example:
+//
+//	client := &http.Client{Transport: registry.Transport(nil)}
+func (r *Registry) Transport(base http.RoundTripper, options ...HeaderOption) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &transport{
+		registry: r,
+		base:     base,
+		opts:     headerOptionsFrom(options),
+	}
+}
+
+// transport is the http.RoundTripper returned by Registry.Transport.
+type transport struct {
+	registry *Registry
+	base     http.RoundTripper
+	opts     *headerOptions
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	header, err := t.registry.EncodeHeader(req.Context())
+	if err != nil {
+		// A RoundTripper must always close the body, even on errors.
+		if req.Body != nil {
+			_ = req.Body.Close()
+		}
+
+		return nil, fmt.Errorf("encoding feature header: %w", err)
+	}
+
+	if header != "" {
+		// A RoundTripper must not modify the original request.
+		req = req.Clone(req.Context())
+		req.Header.Set(t.opts.name, header)
+	}
+
+	return t.base.RoundTrip(req) //nolint:wrapcheck // errors of the base transport are passed through as is
+}
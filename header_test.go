@@ -0,0 +1,447 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpyw/feature"
+)
+
+// TestHeaderCodec tests encoding and decoding the feature header.
+func TestHeaderCodec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip of propagatable keys", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry), feature.WithPropagation())
+		timeout := feature.NewNamed[time.Duration]("timeout", feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx := context.Background()
+		ctx = newUI.WithDisabled(ctx)
+		ctx = maxItems.WithValue(ctx, 100)
+		ctx = region.WithValue(ctx, "eu, west=1")
+		ctx = timeout.WithValue(ctx, 1500*time.Millisecond)
+
+		header, err := registry.EncodeHeader(ctx)
+		if err != nil {
+			t.Fatalf("EncodeHeader() error = %v", err)
+		}
+
+		want := "new-ui=false,max-items=100,region=eu%2C+west%3D1,timeout=1.5s"
+		if header != want {
+			t.Errorf("EncodeHeader() = %q, want %q", header, want)
+		}
+
+		decoded, err := registry.DecodeHeader(context.Background(), header)
+		if err != nil {
+			t.Fatalf("DecodeHeader() error = %v", err)
+		}
+
+		if !newUI.ExplicitlyDisabled(decoded) {
+			t.Error("newUI.ExplicitlyDisabled() = false, want true")
+		}
+
+		if got := maxItems.Get(decoded); got != 100 {
+			t.Errorf("maxItems.Get() = %d, want 100", got)
+		}
+
+		if got := region.Get(decoded); got != "eu, west=1" {
+			t.Errorf("region.Get() = %q, want %q", got, "eu, west=1")
+		}
+
+		if got := timeout.Get(decoded); got != 1500*time.Millisecond {
+			t.Errorf("timeout.Get() = %v, want 1.5s", got)
+		}
+	})
+
+	t.Run("only set propagatable keys are encoded", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		internal := feature.NewNamedBool("internal", feature.WithRegistry(registry))
+		_ = feature.NewNamed[int]("unset", feature.WithRegistry(registry), feature.WithPropagation(), feature.WithDefault(1))
+		anonymous := feature.NewBool(feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx := context.Background()
+		ctx = internal.WithEnabled(ctx)
+		ctx = anonymous.WithEnabled(ctx)
+
+		header, err := registry.EncodeHeader(ctx)
+		if err != nil {
+			t.Fatalf("EncodeHeader() error = %v", err)
+		}
+
+		if header != "" {
+			t.Errorf("EncodeHeader() = %q, want empty", header)
+		}
+	})
+
+	t.Run("unknown and non-propagatable names are reported", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		internal := feature.NewNamedBool("internal", feature.WithRegistry(registry))
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx, err := registry.DecodeHeader(context.Background(), "internal=true, unknown=1, max-items=5")
+
+		var unknownErr *feature.UnknownKeyError
+		if !errors.As(err, &unknownErr) {
+			t.Fatalf("DecodeHeader() error = %v, want *feature.UnknownKeyError", err)
+		}
+
+		checkContains(t, err.Error(), `unknown key "internal"`)
+		checkContains(t, err.Error(), `unknown key "unknown"`)
+
+		if internal.IsSet(ctx) {
+			t.Error("internal.IsSet() = true, want non-propagatable key to be ignored")
+		}
+
+		if got := maxItems.Get(ctx); got != 5 {
+			t.Errorf("maxItems.Get() = %d, want 5", got)
+		}
+	})
+
+	t.Run("malformed and invalid members are reported", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+
+		_, err := registry.DecodeHeader(context.Background(), "max-items,max-items=%zz,max-items=many")
+
+		if !errors.Is(err, feature.ErrMalformedHeader) {
+			t.Errorf("DecodeHeader() error = %v, want %v", err, feature.ErrMalformedHeader)
+		}
+
+		var parseErr *feature.ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("DecodeHeader() error = %v, want *feature.ParseError", err)
+		}
+	})
+
+	t.Run("format errors are reported", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		key := feature.NewNamed[unformattable]("unformattable", feature.WithRegistry(registry), feature.WithPropagation())
+
+		_, err := registry.EncodeHeader(key.WithValue(context.Background(), unformattable{}))
+
+		var formatErr *feature.FormatError
+		if !errors.As(err, &formatErr) || !errors.Is(err, errUnformattable) {
+			t.Errorf("EncodeHeader() error = %v, want *feature.FormatError", err)
+		}
+	})
+
+	t.Run("unsupported types cannot be propagated", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			want := "propagation is not applicable to key tags of unsupported value type []string"
+			if got := fmt.Sprint(recover()); got != want {
+				t.Errorf("NewNamed() panicked with %q, want %q", got, want)
+			}
+		}()
+
+		_ = feature.NewNamed[[]string]("tags", feature.WithPropagation())
+	})
+}
+
+// TestMiddleware tests decoding the feature header of incoming requests.
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+
+	t.Run("decodes header into request context", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+
+		var got feature.BoolInspection
+
+		handler := registry.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = newUI.InspectBool(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(feature.DefaultHeaderName, "new-ui=1,unknown=1")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if !got.Enabled() {
+			t.Errorf("Inspect() = %v, want enabled", got)
+		}
+	})
+
+	t.Run("custom header name", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+
+		var got int
+
+		next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = maxItems.Get(r.Context())
+		})
+		handler := registry.Middleware(feature.WithHeaderName("X-Flags"))(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Flags", "max-items=100")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if got != 100 {
+			t.Errorf("Get() = %d, want 100", got)
+		}
+	})
+
+	t.Run("error handler can reject requests", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+
+		var called bool
+
+		onError := func(w http.ResponseWriter, _ *http.Request, err error) bool {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return false
+		}
+		next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+			called = true
+		})
+		handler := registry.Middleware(feature.WithHeaderErrorHandler(onError))(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(feature.DefaultHeaderName, "unknown=1")
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if called {
+			t.Error("next handler was called, want request to be rejected")
+		}
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("error handler can let requests through", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+
+		var (
+			reported error
+			got      int
+		)
+
+		onError := func(_ http.ResponseWriter, _ *http.Request, err error) bool {
+			reported = err
+
+			return true
+		}
+		next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			got = maxItems.Get(r.Context())
+		})
+		handler := registry.Middleware(feature.WithHeaderErrorHandler(onError))(next)
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(feature.DefaultHeaderName, "unknown=1,max-items=3")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if reported == nil {
+			t.Error("error handler was not called")
+		}
+
+		if got != 3 {
+			t.Errorf("Get() = %d, want 3", got)
+		}
+	})
+}
+
+// TestTransport tests encoding the feature header into outgoing requests.
+func TestTransport(t *testing.T) {
+	t.Parallel()
+
+	t.Run("propagates values across a hop", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+
+		server := httptest.NewServer(registry.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = fmt.Fprintf(w, "%v %v", newUI.Inspect(r.Context()), maxItems.Inspect(r.Context()))
+		})))
+		t.Cleanup(server.Close)
+
+		ctx := context.Background()
+		ctx = newUI.WithEnabled(ctx)
+		ctx = maxItems.WithValue(ctx, 100)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatalf("NewRequestWithContext() error = %v", err)
+		}
+
+		client := &http.Client{Transport: registry.Transport(nil)}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Fatalf("Do() error = %v", err)
+		}
+		defer resp.Body.Close()
+
+		var body [64]byte
+		n, _ := resp.Body.Read(body[:])
+
+		if got, want := string(body[:n]), "new-ui: true max-items: 100"; got != want {
+			t.Errorf("response = %q, want %q", got, want)
+		}
+
+		if req.Header.Get(feature.DefaultHeaderName) != "" {
+			t.Error("original request was modified")
+		}
+	})
+
+	t.Run("no header without set keys", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+
+		var seen []string
+
+		rt := registry.Transport(roundTripFunc(func(r *http.Request) (*http.Response, error) {
+			seen = r.Header.Values(feature.DefaultHeaderName)
+
+			return &http.Response{StatusCode: http.StatusNoContent, Body: http.NoBody}, nil
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Fatalf("RoundTrip() error = %v", err)
+		}
+		defer resp.Body.Close()
+
+		if len(seen) != 0 {
+			t.Errorf("header = %v, want none", seen)
+		}
+	})
+
+	t.Run("encoding errors fail the request and close the body", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		key := feature.NewNamed[unformattable]("unformattable", feature.WithRegistry(registry), feature.WithPropagation())
+
+		rt := registry.Transport(roundTripFunc(func(*http.Request) (*http.Response, error) {
+			t.Error("base transport was called")
+
+			return nil, errors.New("unreachable")
+		}))
+
+		body := &closeRecorder{Reader: strings.NewReader("payload"), closed: false}
+		req := httptest.NewRequest(http.MethodPost, "http://example.com/", body)
+		req = req.WithContext(key.WithValue(req.Context(), unformattable{}))
+
+		if _, err := rt.RoundTrip(req); !errors.Is(err, errUnformattable) { //nolint:bodyclose // no response on error
+			t.Errorf("RoundTrip() error = %v, want %v", err, errUnformattable)
+		}
+
+		if !body.closed {
+			t.Error("request body was not closed")
+		}
+	})
+}
+
+// errUnformattable is returned by unformattable.MarshalText.
+var errUnformattable = errors.New("unformattable")
+
+// unformattable is a value type with a text codec whose MarshalText always fails.
+type unformattable struct{}
+
+func (unformattable) MarshalText() ([]byte, error) {
+	return nil, errUnformattable
+}
+
+func (*unformattable) UnmarshalText([]byte) error {
+	return nil
+}
+
+// closeRecorder is a request body that records whether it was closed.
+type closeRecorder struct {
+	io.Reader
+
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+
+	return nil
+}
+
+// roundTripFunc adapts a function to http.RoundTripper.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func ExampleRegistry_EncodeHeader() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+		Internal    = feature.NewNamedBool("internal", feature.WithRegistry(registry))
+	)
+
+	ctx := context.Background()
+	ctx = EnableNewUI.WithEnabled(ctx)
+	ctx = MaxItems.WithValue(ctx, 100)
+	ctx = Internal.WithEnabled(ctx)
+
+	header, _ := registry.EncodeHeader(ctx)
+	fmt.Println(header)
+
+	// Output:
+	// new-ui=true,max-items=100
+}
+
+func ExampleRegistry_DecodeHeader() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+	)
+
+	ctx, err := registry.DecodeHeader(context.Background(), "new-ui=1,max-items=100,unknown=x")
+
+	fmt.Println(EnableNewUI.Inspect(ctx))
+	fmt.Println(MaxItems.Inspect(ctx))
+	fmt.Println(err)
+
+	// Output:
+	// new-ui: true
+	// max-items: 100
+	// unknown key "unknown"
+}
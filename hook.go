@@ -0,0 +1,151 @@
+package feature
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// Hook is a function called on each evaluation of a key, e.g. by Get, TryGet, Enabled or Inspect,
+// with the context and the result of the evaluation.
+//
+// Hooks are called synchronously, once per accessor call, so they should be fast and must be safe
+// for concurrent use. They must not modify the inspection in place.
+// Reading keys from the registry without evaluating them, e.g. with Snapshot or by codecs
+// such as EncodeHeader, does not call hooks. Nor do rule conditions and rollouts reading other keys.
+type Hook func(ctx context.Context, inspection AnyInspection)
+
+// hookSet is a copy-on-write list of hooks that can be read without locking.
+type hookSet struct {
+	mu    sync.Mutex
+	hooks atomic.Pointer[[]*hookEntry]
+}
+
+// hookEntry wraps a hook so that it can be removed by identity.
+type hookEntry struct {
+	hook Hook
+}
+
+//nolint:gochecknoglobals // global hooks are intentionally process-wide
+var globalHooks hookSet
+
+// AddHook registers a hook called on each evaluation of any key.
+// It returns a function that removes the hook; calling it more than once has no effect.
+//
+// Example:
+//
+//	remove := feature.AddHook(func(ctx context.Context, inspection feature.AnyInspection) {
+//	    evaluations.WithLabelValues(inspection.Name(), inspection.Reason().String()).Inc()
+//	})
+//	defer remove()
+func AddHook(hook Hook) (remove func()) {
+	return globalHooks.add(hook)
+}
+
+// AddHook registers a hook called on each evaluation of a key recorded in the Registry.
+// It returns a function that removes the hook; calling it more than once has no effect.
+func (r *Registry) AddHook(hook Hook) (remove func()) {
+	return r.hooks.add(hook)
+}
+
+// WithHook returns an option that adds a hook called on each evaluation of the key.
+// Unlike hooks added with AddHook, it cannot be removed.
+//
+// Example:
+//
+//	var NewUI = feature.NewNamedBool("new-ui", feature.WithHook(
+//	    func(ctx context.Context, inspection feature.AnyInspection) {
+//	        exposures.Record(ctx, inspection.Name(), inspection.Value())
+//	    },
+//	))
+func WithHook(hook Hook) Option {
+	return func(o *options) {
+		o.hooks = append(o.hooks, hook)
+	}
+}
+
+// add appends the hook and returns a function that removes it.
+func (s *hookSet) add(hook Hook) func() {
+	entry := &hookEntry{hook: hook}
+
+	s.update(func(hooks []*hookEntry) []*hookEntry {
+		return append(hooks, entry)
+	})
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			s.update(func(hooks []*hookEntry) []*hookEntry {
+				for idx, h := range hooks {
+					if h == entry {
+						return append(hooks[:idx:idx], hooks[idx+1:]...)
+					}
+				}
+
+				return hooks
+			})
+		})
+	}
+}
+
+// update replaces the hooks with the result of fn, which receives a copy it may modify.
+func (s *hookSet) update(fn func(hooks []*hookEntry) []*hookEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var current []*hookEntry
+	if p := s.hooks.Load(); p != nil {
+		current = *p
+	}
+
+	next := fn(append([]*hookEntry(nil), current...))
+	s.hooks.Store(&next)
+}
+
+// load returns the current hooks.
+func (s *hookSet) load() []*hookEntry {
+	if p := s.hooks.Load(); p != nil {
+		return *p
+	}
+
+	return nil
+}
+
+// run calls every hook in the set.
+func (s *hookSet) run(ctx context.Context, inspection AnyInspection) {
+	for _, entry := range s.load() {
+		entry.hook(ctx, inspection)
+	}
+}
+
+// hasHooks reports whether any hook applies to the key.
+func (k key[V]) hasHooks() bool {
+	return len(k.hooks) > 0 ||
+		len(globalHooks.load()) > 0 ||
+		(k.registry != nil && len(k.registry.hooks.load()) > 0) ||
+		k.hasExpiredHooks()
+}
+
+// runHooks calls the hooks of the key, of its registry and the global hooks, in that order,
+// followed by the expired hooks if the key is past its expiry.
+// The inspection is only converted to an AnyInspection when a hook is registered.
+func (k key[V]) runHooks(ctx context.Context, inspection Inspection[V]) {
+	if !k.hasHooks() {
+		return
+	}
+
+	erased := AnyInspection{inspection: inspection}
+
+	for _, hook := range k.hooks {
+		hook(ctx, erased)
+	}
+
+	if k.registry != nil {
+		k.registry.hooks.run(ctx, erased)
+	}
+
+	globalHooks.run(ctx, erased)
+
+	k.runExpiredHooks(ctx, erased)
+}
@@ -0,0 +1,274 @@
+package feature_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestHooks tests hooks called on key evaluations.
+func TestHooks(t *testing.T) {
+	t.Parallel()
+
+	t.Run("key hooks fire once per accessor call", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+
+		key := feature.NewNamedBool("new-ui", feature.WithHook(func(_ context.Context, inspection feature.AnyInspection) {
+			calls = append(calls, inspection.String())
+		}))
+		ctx := key.WithEnabled(context.Background())
+
+		accessors := []func(){
+			func() { _ = key.Get(ctx) },
+			func() { _, _ = key.TryGet(ctx) },
+			func() { _ = key.GetOrDefault(ctx, false) },
+			func() { _ = key.MustGet(ctx) },
+			func() { _ = key.IsSet(ctx) },
+			func() { _ = key.IsNotSet(ctx) },
+			func() { _ = key.Inspect(ctx) },
+			func() { _ = key.InspectBool(ctx) },
+			func() { _ = key.Enabled(ctx) },
+			func() { _ = key.Disabled(ctx) },
+			func() { _ = key.ExplicitlyDisabled(ctx) },
+		}
+
+		for idx, accessor := range accessors {
+			accessor()
+
+			if len(calls) != idx+1 {
+				t.Fatalf("accessor #%d: hook called %d times in total, want %d", idx, len(calls), idx+1)
+			}
+		}
+
+		if calls[0] != "new-ui: true" {
+			t.Errorf("hook inspection = %q, want %q", calls[0], "new-ui: true")
+		}
+	})
+
+	t.Run("hooks receive the key, the context and the evaluation", func(t *testing.T) {
+		t.Parallel()
+
+		type ctxKey struct{}
+
+		var (
+			gotKey    feature.AnyKey
+			gotCtx    context.Context
+			gotReason feature.Reason
+		)
+
+		hook := func(ctx context.Context, inspection feature.AnyInspection) {
+			gotKey, gotCtx, gotReason = inspection.Key(), ctx, inspection.Reason()
+		}
+		key := feature.NewNamed[int]("max-items", feature.WithDefault(100), feature.WithHook(hook))
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+		_ = key.Get(ctx)
+
+		if gotKey != key {
+			t.Errorf("Key() = %v, want %v", gotKey, key)
+		}
+
+		if gotCtx != ctx {
+			t.Error("hook did not receive the evaluated context")
+		}
+
+		if gotReason != feature.ReasonDefault {
+			t.Errorf("Reason() = %v, want %v", gotReason, feature.ReasonDefault)
+		}
+	})
+
+	t.Run("registry hooks can be removed", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		registered := feature.NewNamed[int]("registered", feature.WithRegistry(registry))
+		unregistered := feature.NewNamed[int]("unregistered")
+
+		var names []string
+
+		remove := registry.AddHook(func(_ context.Context, inspection feature.AnyInspection) {
+			names = append(names, inspection.Name())
+		})
+
+		ctx := context.Background()
+		_ = registered.Get(ctx)
+		_ = unregistered.Get(ctx)
+
+		remove()
+		remove()
+
+		_ = registered.Get(ctx)
+
+		if len(names) != 1 || names[0] != "registered" {
+			t.Errorf("hook called for %v, want [registered]", names)
+		}
+	})
+
+	t.Run("global hooks apply to every key", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[string]("region")
+
+		var calls atomic.Int32
+
+		remove := feature.AddHook(func(_ context.Context, inspection feature.AnyInspection) {
+			if inspection.Key() == key {
+				calls.Add(1)
+			}
+		})
+
+		_ = key.Get(context.Background())
+
+		remove()
+
+		_ = key.Get(context.Background())
+
+		if got := calls.Load(); got != 1 {
+			t.Errorf("hook called %d times, want 1", got)
+		}
+	})
+
+	t.Run("hooks run in order of key, registry and global", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			mu    sync.Mutex
+			order []string
+		)
+
+		record := func(name string) feature.Hook {
+			return func(context.Context, feature.AnyInspection) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				order = append(order, name)
+			}
+		}
+
+		registry := feature.NewRegistry()
+		key := feature.NewNamed[int]("ordered", feature.WithRegistry(registry), feature.WithHook(record("key")))
+
+		defer registry.AddHook(record("registry"))()
+		defer feature.AddHook(func(ctx context.Context, inspection feature.AnyInspection) {
+			if inspection.Key() == key {
+				record("global")(ctx, inspection)
+			}
+		})()
+
+		_ = key.Get(context.Background())
+
+		if got := fmt.Sprint(order); got != "[key registry global]" {
+			t.Errorf("hooks ran in order %s, want [key registry global]", got)
+		}
+	})
+
+	t.Run("snapshots and codecs do not fire hooks", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+
+		registry := feature.NewRegistry()
+		key := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+
+		defer registry.AddHook(func(context.Context, feature.AnyInspection) { calls++ })()
+
+		ctx := key.WithEnabled(context.Background())
+		_ = registry.Snapshot(ctx)
+
+		if _, err := registry.EncodeHeader(ctx); err != nil {
+			t.Fatalf("EncodeHeader() error = %v", err)
+		}
+
+		if calls != 0 {
+			t.Errorf("hook called %d times, want 0", calls)
+		}
+	})
+
+	t.Run("snapshots of computed keys do not fire hooks of the keys they read", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+
+		registry := feature.NewRegistry()
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+		userID := feature.NewNamed[string]("user-id", feature.WithRegistry(registry))
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithRules(
+			feature.Rule[int]{Name: "eu", When: feature.Equals(region, "eu"), Value: 50},
+		))
+		_ = feature.NewNamedBool("checkout", feature.WithRegistry(registry), feature.WithRollout(50, userID))
+
+		rules, err := feature.ParseRules[int](registry, []byte(`[
+			{"name": "eu", "when": {"key": "region", "equals": "eu"}, "value": 50}
+		]`))
+		if err != nil {
+			t.Fatalf("ParseRules() error = %v", err)
+		}
+
+		_ = feature.NewNamed[int]("min-items", feature.WithRegistry(registry), feature.WithRules(rules...))
+
+		defer registry.AddHook(func(context.Context, feature.AnyInspection) { calls++ })()
+
+		ctx := feature.With(context.Background(), region.Bind("eu"), userID.Bind("user-1"))
+		_ = registry.Snapshot(ctx)
+
+		if calls != 0 {
+			t.Errorf("hook called %d times, want 0", calls)
+		}
+	})
+
+	t.Run("hooks can be added and removed concurrently", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		key := feature.NewNamedBool("concurrent", feature.WithRegistry(registry))
+		ctx := context.Background()
+
+		var wg sync.WaitGroup
+
+		for idx := 0; idx < 8; idx++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				remove := registry.AddHook(func(context.Context, feature.AnyInspection) {})
+				_ = key.Enabled(ctx)
+				remove()
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				_ = key.Enabled(ctx)
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func ExampleRegistry_AddHook() {
+	registry := feature.NewRegistry()
+
+	var EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+
+	remove := registry.AddHook(func(_ context.Context, inspection feature.AnyInspection) {
+		fmt.Println("evaluated", inspection.Name(), inspection.Value(), inspection.Reason())
+	})
+	defer remove()
+
+	ctx := EnableNewUI.WithEnabled(context.Background())
+	if EnableNewUI.Enabled(ctx) {
+		fmt.Println("showing new UI")
+	}
+
+	// Output:
+	// evaluated new-ui true context-override
+	// showing new UI
+}
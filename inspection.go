@@ -1,6 +1,31 @@
 package feature
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNotSet is matched by every *NotSetError with errors.Is.
+var ErrNotSet = errors.New("key is not set")
+
+// NotSetError is returned by Require, and MustGet panics with it, when a key is not set in the context.
+type NotSetError struct {
+	// Key is the name of the key that is not set.
+	Key string
+	// Type is the value type of the key.
+	Type reflect.Type
+}
+
+// Error implements the error interface.
+func (e *NotSetError) Error() string {
+	return fmt.Sprintf("key %s of type %s is not set in context", e.Key, e.Type)
+}
+
+// Is reports whether the target is ErrNotSet, so that errors.Is(err, ErrNotSet) holds.
+func (e *NotSetError) Is(target error) bool {
+	return target == ErrNotSet
+}
 
 // Inspection holds the result of inspecting a key's value in a context.
 // It captures both the key, its value, and whether the value was set.
@@ -10,21 +35,37 @@ type Inspection[V any] struct {
 	// Key is the key that was inspected.
 	Key Key[V]
 	// Value is the value retrieved from the context.
-	// If Ok is false, this will be the zero value of type V.
+	// If Ok is false, this will be the value computed by the rules (see WithRules) or the rollout
+	// (see WithRollout) of the key, the default value of the key (see WithDefault),
+	// or the zero value of type V if there is none.
 	Value V
-	// Ok indicates whether the key was set in the context.
+	// Ok indicates whether the key was set in the context or forced with Override.
 	Ok bool
+	// Reason describes why Value has its value.
+	Reason Reason
+	// Rule is the name of the rule that computed Value if Reason is ReasonRuleMatch,
+	// or empty otherwise.
+	Rule string
 }
 
 // Get returns the value from the inspection.
-// If the key was not set, it returns the zero value of type V.
+// If the key was not set, it returns the value computed by the rules of the key
+// or the default value of the key, if any, or the zero value of type V.
 func (i Inspection[V]) Get() V {
 	return i.Value
 }
 
 // TryGet returns the value and whether it was set.
+// If the key was not set, it returns the zero value of type V and false,
+// even if the key has a default value.
 func (i Inspection[V]) TryGet() (V, bool) {
-	return i.Value, i.Ok
+	if !i.Ok {
+		var zero V
+
+		return zero, false
+	}
+
+	return i.Value, true
 }
 
 // GetOrDefault returns the value if set, otherwise returns the provided default.
@@ -36,13 +77,27 @@ func (i Inspection[V]) GetOrDefault(defaultValue V) V {
 	return defaultValue
 }
 
-// MustGet returns the value if set, otherwise panics.
+// MustGet returns the value if set, otherwise panics with a *NotSetError.
+// The default value of the key is not considered.
 func (i Inspection[V]) MustGet() V {
+	value, err := i.Require()
+	if err != nil {
+		panic(err)
+	}
+
+	return value
+}
+
+// Require returns the value if set, otherwise the zero value of type V and a *NotSetError.
+// The default value of the key is not considered.
+func (i Inspection[V]) Require() (V, error) {
 	if !i.Ok {
-		panic(fmt.Sprintf("key %s is not set in context", i.Key.String()))
+		var zero V
+
+		return zero, &NotSetError{Key: i.Key.String(), Type: typeOf[V]()}
 	}
 
-	return i.Value
+	return i.Value, nil
 }
 
 // IsSet returns true if the key was set in the context.
@@ -56,14 +111,42 @@ func (i Inspection[V]) IsNotSet() bool {
 }
 
 // String returns a string representation combining the key name and its value.
-// Format: "<key-name>: <value>" or "<key-name>: <not set>".
+// Format: "<key-name>: <value>" if set in the context, "<key-name>: <not set>" if nothing
+// computed a value, "<key-name>: <value> (rule <rule-name>)" if computed by a rule,
+// or "<key-name>: <value> (<reason>)" otherwise, e.g. "max-items: 100 (default)".
+// An inspection built as a struct literal with Ok set but no Reason is treated as set in the context.
 // This implements fmt.Stringer.
 func (i Inspection[V]) String() string {
-	if !i.Ok {
+	switch i.Reason {
+	case ReasonContextOverride:
+		return fmt.Sprintf("%s: %v", i.Key.String(), i.Value)
+	case ReasonRuleMatch:
+		return fmt.Sprintf("%s: %v (rule %s)", i.Key.String(), i.Value, i.Rule)
+	case ReasonNotSet:
+		if i.Ok {
+			return fmt.Sprintf("%s: %v", i.Key.String(), i.Value)
+		}
+
 		return i.Key.String() + ": <not set>"
+	default:
+		return fmt.Sprintf("%s: %v (%s)", i.Key.String(), i.Value, i.Reason)
 	}
+}
+
+func (i Inspection[V]) anyKey() AnyKey {
+	return i.Key
+}
+
+func (i Inspection[V]) anyValue() any {
+	return i.Value
+}
+
+func (i Inspection[V]) anyReason() Reason {
+	return i.Reason
+}
 
-	return fmt.Sprintf("%s: %v", i.Key.String(), i.Value)
+func (i Inspection[V]) anyRule() string {
+	return i.Rule
 }
 
 // BoolInspection is a specialized Inspection for boolean feature flags.
@@ -73,7 +156,8 @@ type BoolInspection struct {
 }
 
 // Enabled returns true if the feature flag is set to true.
-// If the key was not set, it returns false (the zero value).
+// If the key was not set, it returns the value computed by the rules or the rollout of the key
+// or the default value of the key, if any, or false (the zero value).
 func (i BoolInspection) Enabled() bool {
 	return i.Value
 }
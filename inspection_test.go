@@ -2,6 +2,7 @@ package feature_test
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -38,6 +39,24 @@ func TestInspectionString(t *testing.T) {
 		}
 	})
 
+	t.Run("struct literal without reason", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("n")
+
+		// Built as before Reason and Rule existed.
+		set := feature.Inspection[int]{Key: key, Value: 5, Ok: true}     //nolint:exhaustruct // see above
+		notSet := feature.Inspection[int]{Key: key, Value: 0, Ok: false} //nolint:exhaustruct // see above
+
+		if got, want := set.String(), "n: 5"; got != want {
+			t.Errorf("Inspection.String() = %q, want %q", got, want)
+		}
+
+		if got, want := notSet.String(), "n: <not set>"; got != want {
+			t.Errorf("Inspection.String() = %q, want %q", got, want)
+		}
+	})
+
 	t.Run("set key shows name and value", func(t *testing.T) {
 		t.Parallel()
 
@@ -60,6 +79,42 @@ func TestInspectionString(t *testing.T) {
 		}
 	})
 
+	t.Run("unset key with default shows default", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-items", feature.WithDefault(100))
+		inspection := key.Inspect(context.Background())
+
+		if inspection.Ok {
+			t.Error("Inspection.Ok = true, want false")
+		}
+
+		if inspection.Value != 100 {
+			t.Errorf("Inspection.Value = %d, want 100", inspection.Value)
+		}
+
+		if val, ok := inspection.TryGet(); ok || val != 0 {
+			t.Errorf("Inspection.TryGet() = (%d, %v), want (0, false)", val, ok)
+		}
+
+		want := "max-items: 100 (default)"
+		if got := inspection.String(); got != want {
+			t.Errorf("Inspection.String() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("set key with default shows value", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-items", feature.WithDefault(100))
+		inspection := key.Inspect(key.WithValue(context.Background(), 5))
+
+		want := "max-items: 5"
+		if got := inspection.String(); got != want {
+			t.Errorf("Inspection.String() = %q, want %q", got, want)
+		}
+	})
+
 	t.Run("anonymous key shows call site info in name", func(t *testing.T) {
 		t.Parallel()
 
@@ -232,6 +287,21 @@ func TestInspectionHelperMethods(t *testing.T) {
 
 		inspection.MustGet()
 	})
+
+	t.Run("Require", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("require-test")
+
+		if _, err := key.Inspect(context.Background()).Require(); !errors.Is(err, feature.ErrNotSet) {
+			t.Errorf("Inspection.Require() error = %v, want ErrNotSet", err)
+		}
+
+		got, err := key.Inspect(key.WithValue(context.Background(), 42)).Require()
+		if err != nil || got != 42 {
+			t.Errorf("Inspection.Require() = (%d, %v), want (42, <nil>)", got, err)
+		}
+	})
 }
 
 func TestBoolInspectionHelperMethods(t *testing.T) {
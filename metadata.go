@@ -0,0 +1,146 @@
+package feature
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+)
+
+// DefaultMetadataPrefix is prepended to key names to form metadata keys
+// unless WithMetadataPrefix is given.
+const DefaultMetadataPrefix = "feature-"
+
+// MetadataOption is a function that configures the behavior of the metadata codec.
+type MetadataOption func(*metadataOptions)
+
+// metadataOptions configures the behavior of the metadata codec.
+type metadataOptions struct {
+	prefix string
+}
+
+// WithMetadataPrefix returns an option that sets the prefix prepended to key names
+// to form metadata keys.
+func WithMetadataPrefix(prefix string) MetadataOption {
+	return func(o *metadataOptions) {
+		o.prefix = prefix
+	}
+}
+
+// metadataOptionsFrom applies the given option functions to create a configured metadataOptions.
+func metadataOptionsFrom(options []MetadataOption) *metadataOptions {
+	opts := &metadataOptions{
+		prefix: DefaultMetadataPrefix,
+	}
+	for _, optFn := range options {
+		optFn(opts)
+	}
+
+	return opts
+}
+
+// metadataKey returns the metadata key for the key name.
+// Metadata keys are lower-cased, since gRPC and HTTP/2 lower-case them on the wire.
+func (o *metadataOptions) metadataKey(name string) string {
+	return strings.ToLower(o.prefix + name)
+}
+
+// EncodeMetadata encodes the propagatable keys set in the context into metadata,
+// the map[string][]string shape used by gRPC metadata and many message queue headers.
+//
+// Each key set in the context produces one entry whose key is the metadata prefix followed
+// by the lower-cased key name (e.g. "feature-new-ui") and whose single value is the textual
+// representation of the value (e.g. "true"). Only keys created with WithPropagation are encoded.
+//
+// Example:
+//
+//	md, err := registry.EncodeMetadata(ctx)
+//	if err != nil {
+//	    return err
+//	}
+//	ctx = metadata.NewOutgoingContext(ctx, md) // google.golang.org/grpc/metadata
+func (r *Registry) EncodeMetadata(ctx context.Context, options ...MetadataOption) (map[string][]string, error) {
+	opts := metadataOptionsFrom(options)
+
+	values, err := r.encodePropagated(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	md := make(map[string][]string, len(values))
+	for _, v := range values {
+		md[opts.metadataKey(v.name)] = []string{v.text}
+	}
+
+	return md, nil
+}
+
+// DecodeMetadata returns a new context with the values decoded from metadata produced by
+// EncodeMetadata. Metadata keys are matched case-insensitively and entries without the
+// metadata prefix are ignored. If an entry has several values, the last one wins, just like
+// a later WithValue shadows an earlier one.
+//
+// Names are resolved against the propagatable keys recorded in the Registry.
+// If some entries cannot be decoded, DecodeMetadata still returns a context containing the
+// values that could, together with an error joining an *UnknownKeyError, *ParseError or
+// *ValidationError for each offending entry.
+func (r *Registry) DecodeMetadata(
+	ctx context.Context,
+	md map[string][]string,
+	options ...MetadataOption,
+) (context.Context, error) {
+	opts := metadataOptionsFrom(options)
+	prefix := strings.ToLower(opts.prefix)
+
+	// Index prefixed entries by their lower-cased name.
+	pending := make(map[string][]string)
+
+	for mdKey, values := range md {
+		lower := strings.ToLower(mdKey)
+		if strings.HasPrefix(lower, prefix) && len(values) > 0 {
+			pending[lower[len(prefix):]] = values
+		}
+	}
+
+	var (
+		bindings []Binding
+		errs     []error
+	)
+
+	for _, entry := range r.snapshot() {
+		if !entry.Propagated || entry.Anonymous {
+			continue
+		}
+
+		name := strings.ToLower(entry.Name)
+
+		values, ok := pending[name]
+		if !ok {
+			continue
+		}
+
+		delete(pending, name)
+
+		binding, err := r.decodePropagated(entry.Name, values[len(values)-1])
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		bindings = append(bindings, binding)
+	}
+
+	unknown := make([]string, 0, len(pending))
+	for name := range pending {
+		unknown = append(unknown, name)
+	}
+
+	sort.Strings(unknown)
+
+	for _, name := range unknown {
+		errs = append(errs, &UnknownKeyError{Name: name})
+	}
+
+	return withBindings(ctx, bindings), errors.Join(errs...)
+}
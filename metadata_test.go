@@ -0,0 +1,162 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestMetadataCodec tests encoding and decoding feature values as metadata.
+func TestMetadataCodec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("round trip of propagatable keys", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		maxItems := feature.NewNamed[int]("maxItems", feature.WithRegistry(registry), feature.WithPropagation())
+		internal := feature.NewNamedBool("internal", feature.WithRegistry(registry))
+		_ = feature.NewNamed[string]("unset", feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx := context.Background()
+		ctx = newUI.WithEnabled(ctx)
+		ctx = maxItems.WithValue(ctx, 100)
+		ctx = internal.WithEnabled(ctx)
+
+		md, err := registry.EncodeMetadata(ctx)
+		if err != nil {
+			t.Fatalf("EncodeMetadata() error = %v", err)
+		}
+
+		want := map[string][]string{
+			"feature-new-ui":   {"true"},
+			"feature-maxitems": {"100"},
+		}
+		if !reflect.DeepEqual(md, want) {
+			t.Errorf("EncodeMetadata() = %v, want %v", md, want)
+		}
+
+		decoded, err := registry.DecodeMetadata(context.Background(), md)
+		if err != nil {
+			t.Fatalf("DecodeMetadata() error = %v", err)
+		}
+
+		if !newUI.Enabled(decoded) {
+			t.Error("newUI.Enabled() = false, want true")
+		}
+
+		if got := maxItems.Get(decoded); got != 100 {
+			t.Errorf("maxItems.Get() = %d, want 100", got)
+		}
+
+		if internal.IsSet(decoded) {
+			t.Error("internal.IsSet() = true, want false")
+		}
+	})
+
+	t.Run("custom prefix", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+
+		md, err := registry.EncodeMetadata(newUI.WithEnabled(context.Background()), feature.WithMetadataPrefix("X-Flag-"))
+		if err != nil {
+			t.Fatalf("EncodeMetadata() error = %v", err)
+		}
+
+		if _, ok := md["x-flag-new-ui"]; !ok {
+			t.Errorf("EncodeMetadata() = %v, want key x-flag-new-ui", md)
+		}
+
+		decoded, err := registry.DecodeMetadata(context.Background(), md, feature.WithMetadataPrefix("X-Flag-"))
+		if err != nil {
+			t.Fatalf("DecodeMetadata() error = %v", err)
+		}
+
+		if !newUI.Enabled(decoded) {
+			t.Error("Enabled() = false, want true")
+		}
+	})
+
+	t.Run("keys are matched case-insensitively and last value wins", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx, err := registry.DecodeMetadata(context.Background(), map[string][]string{
+			"Feature-Max-Items": {"1", "2"},
+			"authorization":     {"Bearer token"},
+		})
+		if err != nil {
+			t.Fatalf("DecodeMetadata() error = %v", err)
+		}
+
+		if got := maxItems.Get(ctx); got != 2 {
+			t.Errorf("Get() = %d, want 2", got)
+		}
+	})
+
+	t.Run("unknown names and invalid values are reported", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+		_ = feature.NewNamedBool("internal", feature.WithRegistry(registry))
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry), feature.WithPropagation())
+
+		ctx, err := registry.DecodeMetadata(context.Background(), map[string][]string{
+			"feature-max-items": {"many"},
+			"feature-internal":  {"true"},
+			"feature-unknown":   {"1"},
+			"feature-region":    {"eu"},
+		})
+
+		var parseErr *feature.ParseError
+		if !errors.As(err, &parseErr) || parseErr.Key != "max-items" {
+			t.Errorf("DecodeMetadata() error = %v, want *feature.ParseError for max-items", err)
+		}
+
+		checkContains(t, fmt.Sprint(err), `unknown key "internal"`)
+		checkContains(t, fmt.Sprint(err), `unknown key "unknown"`)
+
+		if maxItems.IsSet(ctx) {
+			t.Error("maxItems.IsSet() = true, want invalid value to be skipped")
+		}
+
+		if got := region.Get(ctx); got != "eu" {
+			t.Errorf("region.Get() = %q, want %q", got, "eu")
+		}
+	})
+}
+
+func ExampleRegistry_EncodeMetadata() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry), feature.WithPropagation())
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithPropagation())
+	)
+
+	ctx := context.Background()
+	ctx = EnableNewUI.WithEnabled(ctx)
+	ctx = MaxItems.WithValue(ctx, 100)
+
+	md, _ := registry.EncodeMetadata(ctx)
+	fmt.Println(md)
+
+	decoded, _ := registry.DecodeMetadata(context.Background(), md)
+	fmt.Println(EnableNewUI.Inspect(decoded))
+	fmt.Println(MaxItems.Inspect(decoded))
+
+	// Output:
+	// map[feature-max-items:[100] feature-new-ui:[true]]
+	// new-ui: true
+	// max-items: 100
+}
@@ -0,0 +1,144 @@
+package feature
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// OverrideOption is a function that configures the behavior of an override set with Override.
+type OverrideOption func(*overrideOptions)
+
+// overrideOptions configures the behavior of an override.
+type overrideOptions struct {
+	afterContext bool
+}
+
+// AfterContext returns an option that makes values set in the context win over the override,
+// so that the override only replaces the value of contexts in which the key is not set.
+// By default, the override wins over values set in the context.
+func AfterContext() OverrideOption {
+	return func(o *overrideOptions) {
+		o.afterContext = true
+	}
+}
+
+// override is a value forced for a key process-wide.
+type override struct {
+	value        any
+	afterContext bool
+}
+
+// overrides holds the current overrides by key identity.
+// It is replaced as a whole on each change so that reads need no locking.
+//
+//nolint:gochecknoglobals // overrides are intentionally process-wide
+var (
+	overrides   atomic.Pointer[map[*opaque]override]
+	overridesMu sync.Mutex
+)
+
+// Override forces the value of the key for every context in the process, e.g. to turn on
+// a kill switch during an incident or to pin a flag in integration tests.
+//
+// The override is consulted by TryGet and every accessor built on it: the key reports the value
+// as set, and Inspection.Reason is ReasonOverride. By default the override wins over values
+// set in the context; pass AfterContext to let them win instead.
+//
+// Overrides are local to the process: codecs such as EncodeHeader, EncodeMetadata and EncodeBaggage
+// only propagate the values set in the context, so that an override never leaks downstream.
+//
+// Override returns a function that restores the override of the key that was in effect
+// before the call, if any. Calling it more than once has no effect. Overrides of the same key
+// should be restored in reverse order.
+//
+// Override is safe for concurrent use. If the value is rejected by a validator given with
+// WithValidator, it panics with a *ValidationError.
+//
+// Example:
+//
+//	restore := feature.Override(KillSwitch, true)
+//	defer restore()
+func Override[V any](key Key[V], value V, options ...OverrideOption) (restore func()) {
+	opts := &overrideOptions{
+		afterContext: false,
+	}
+	for _, optFn := range options {
+		optFn(opts)
+	}
+
+	k := key.downcast()
+	if err := k.validate(value); err != nil {
+		panic(err)
+	}
+
+	previous, existed := setOverride(k.ident, &override{value: value, afterContext: opts.afterContext})
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			if existed {
+				setOverride(k.ident, &previous)
+			} else {
+				setOverride(k.ident, nil)
+			}
+		})
+	}
+}
+
+// setOverride replaces the override of the key, removing it if o is nil,
+// and returns the previous override, if any.
+func setOverride(ident *opaque, o *override) (override, bool) {
+	overridesMu.Lock()
+	defer overridesMu.Unlock()
+
+	current := loadOverrides()
+	previous, existed := current[ident]
+
+	next := make(map[*opaque]override, len(current)+1)
+	for id, v := range current {
+		next[id] = v
+	}
+
+	if o != nil {
+		next[ident] = *o
+	} else {
+		delete(next, ident)
+	}
+
+	overrides.Store(&next)
+
+	return previous, existed
+}
+
+// loadOverrides returns the current overrides. The result must not be modified.
+func loadOverrides() map[*opaque]override {
+	if p := overrides.Load(); p != nil {
+		return *p
+	}
+
+	return nil
+}
+
+// lookup retrieves the value of the key from the overrides and the context without calling hooks.
+// It returns ReasonOverride or ReasonContextOverride depending on where the value was found.
+func (k key[V]) lookup(ctx context.Context) (V, Reason, bool) {
+	o, overridden := loadOverrides()[k.ident]
+
+	if overridden && !o.afterContext {
+		return o.value.(V), ReasonOverride, true //nolint:forcetypeassert // overrides are always set through Override[V]
+	}
+
+	if val, ok := ctx.Value(k.ident).(V); ok {
+		return val, ReasonContextOverride, true
+	}
+
+	if overridden {
+		return o.value.(V), ReasonOverride, true //nolint:forcetypeassert // overrides are always set through Override[V]
+	}
+
+	var zero V
+
+	return zero, ReasonNotSet, false
+}
@@ -0,0 +1,178 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestOverride tests process-wide overrides.
+func TestOverride(t *testing.T) {
+	t.Parallel()
+
+	t.Run("overrides win over the context by default", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamedBool("kill-switch")
+		ctx := key.WithDisabled(context.Background())
+
+		restore := feature.Override(key, true)
+
+		if !key.Enabled(ctx) || !key.Enabled(context.Background()) {
+			t.Error("Enabled() = false, want override to apply")
+		}
+
+		if v, ok := key.TryGet(context.Background()); !v || !ok {
+			t.Errorf("TryGet() = %v, %v, want true, true", v, ok)
+		}
+
+		inspection := key.Inspect(ctx)
+		if inspection.Reason != feature.ReasonOverride || inspection.String() != "kill-switch: true (override)" {
+			t.Errorf("Inspect() = %v (reason %v), want override", inspection, inspection.Reason)
+		}
+
+		restore()
+
+		if !key.ExplicitlyDisabled(ctx) || key.IsSet(context.Background()) {
+			t.Error("restore() did not remove the override")
+		}
+	})
+
+	t.Run("AfterContext lets the context win", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-items", feature.WithDefault(10))
+		defer feature.Override(key, 100, feature.AfterContext())()
+
+		if got := key.Get(key.WithValue(context.Background(), 5)); got != 5 {
+			t.Errorf("Get() = %d, want context value 5", got)
+		}
+
+		inspection := key.Inspect(context.Background())
+		if inspection.Value != 100 || inspection.Reason != feature.ReasonOverride || !inspection.Ok {
+			t.Errorf("Inspect() = %+v, want override 100", inspection)
+		}
+	})
+
+	t.Run("restore brings back the previous override", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[string]("region")
+		restoreFirst := feature.Override(key, "eu")
+		restoreSecond := feature.Override(key, "us")
+
+		if got := key.Get(context.Background()); got != "us" {
+			t.Errorf("Get() = %q, want %q", got, "us")
+		}
+
+		restoreSecond()
+		restoreSecond()
+
+		if got := key.Get(context.Background()); got != "eu" {
+			t.Errorf("Get() = %q, want %q after restoring the second override", got, "eu")
+		}
+
+		restoreFirst()
+
+		if key.IsSet(context.Background()) {
+			t.Error("IsSet() = true, want false after restoring all overrides")
+		}
+	})
+
+	t.Run("overrides are validated", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-retries", feature.WithValidator(nonNegative))
+
+		defer func() {
+			r := recover()
+
+			err, ok := r.(error)
+			if !ok || !errors.Is(err, errNegative) {
+				t.Errorf("Override() panic = %v, want *feature.ValidationError", r)
+			}
+
+			if key.IsSet(context.Background()) {
+				t.Error("IsSet() = true, want invalid override to be rejected")
+			}
+		}()
+
+		_ = feature.Override(key, -1)
+	})
+
+	t.Run("overrides are not propagated", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		kill := feature.NewNamedBool("kill", feature.WithRegistry(registry), feature.WithPropagation())
+
+		restore := feature.Override(kill, true)
+		defer restore()
+
+		header, err := registry.EncodeHeader(context.Background())
+		if err != nil || header != "" {
+			t.Errorf("EncodeHeader() = (%q, %v), want empty", header, err)
+		}
+
+		md, err := registry.EncodeMetadata(context.Background())
+		if err != nil || len(md) != 0 {
+			t.Errorf("EncodeMetadata() = (%v, %v), want empty", md, err)
+		}
+
+		baggage, err := registry.EncodeBaggage(context.Background())
+		if err != nil || baggage != "" {
+			t.Errorf("EncodeBaggage() = (%q, %v), want empty", baggage, err)
+		}
+
+		header, err = registry.EncodeHeader(kill.WithDisabled(context.Background()))
+		if err != nil || header != "kill=false" {
+			t.Errorf("EncodeHeader() = (%q, %v), want the context value %q", header, err, "kill=false")
+		}
+	})
+
+	t.Run("overrides are safe for concurrent use", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamedBool("concurrent-override")
+
+		var wg sync.WaitGroup
+
+		for idx := 0; idx < 8; idx++ {
+			wg.Add(2)
+
+			go func() {
+				defer wg.Done()
+
+				feature.Override(key, true)()
+			}()
+
+			go func() {
+				defer wg.Done()
+
+				_ = key.Enabled(context.Background())
+			}()
+		}
+
+		wg.Wait()
+	})
+}
+
+func ExampleOverride() {
+	var KillSwitch = feature.NewNamedBool("kill-switch")
+
+	ctx := KillSwitch.WithDisabled(context.Background())
+
+	restore := feature.Override(KillSwitch, true)
+	fmt.Println(KillSwitch.Inspect(ctx))
+
+	restore()
+	fmt.Println(KillSwitch.Inspect(ctx))
+
+	// Output:
+	// kill-switch: true (override)
+	// kill-switch: false
+}
@@ -0,0 +1,54 @@
+package feature
+
+import (
+	"context"
+)
+
+// propagatedValue is the textual value of a propagatable key set in a context.
+type propagatedValue struct {
+	name string
+	text string
+}
+
+// encodePropagated formats the values of all propagatable keys set in the context,
+// in registration order.
+func (r *Registry) encodePropagated(ctx context.Context) ([]propagatedValue, error) {
+	var values []propagatedValue
+
+	for _, entry := range r.snapshot() {
+		if !entry.Propagated || entry.Anonymous {
+			continue
+		}
+
+		text, ok, err := entry.Key.formatAny(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if ok {
+			values = append(values, propagatedValue{name: entry.Name, text: text})
+		}
+	}
+
+	return values, nil
+}
+
+// decodePropagated parses the text for the propagatable key with the given name and
+// returns the value bound to the key, to be stored by withBindings together with the
+// other decoded values in a single context node.
+// It returns an *UnknownKeyError if no propagatable key has the name.
+func (r *Registry) decodePropagated(name, text string) (Binding, error) {
+	var zero Binding
+
+	entry, ok := r.lookupNamed(name)
+	if !ok || !entry.Propagated {
+		return zero, &UnknownKeyError{Name: name}
+	}
+
+	value, err := entry.Key.parseAny(text)
+	if err != nil {
+		return zero, err
+	}
+
+	return entry.Key.bindAny(value), nil
+}
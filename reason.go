@@ -0,0 +1,54 @@
+package feature
+
+import (
+	"fmt"
+)
+
+// Reason describes why an Inspection has its value.
+type Reason int
+
+const (
+	// ReasonNotSet means that the key is not set in the context and nothing computed a value,
+	// so the value is the zero value of the key's value type.
+	ReasonNotSet Reason = iota
+	// ReasonContextOverride means that the value was set in the context, e.g. with WithValue.
+	ReasonContextOverride
+	// ReasonDefault means that the value is the default value given with WithDefault.
+	ReasonDefault
+	// ReasonRuleMatch means that the value was computed by a rule given with WithRules.
+	// The name of the rule is reported in Inspection.Rule.
+	ReasonRuleMatch
+	// ReasonRollout means that the value was computed by the rollout given with WithRollout.
+	ReasonRollout
+	// ReasonOverride means that the value was forced process-wide with Override.
+	ReasonOverride
+)
+
+// reasonNames maps each Reason to its textual representation.
+//
+//nolint:gochecknoglobals // read-only lookup table
+var reasonNames = map[Reason]string{
+	ReasonNotSet:          "not-set",
+	ReasonContextOverride: "context-override",
+	ReasonDefault:         "default",
+	ReasonRuleMatch:       "rule-match",
+	ReasonRollout:         "rollout",
+	ReasonOverride:        "override",
+}
+
+// String returns the textual representation of the reason, e.g. "context-override".
+// This implements fmt.Stringer.
+func (r Reason) String() string {
+	if name, ok := reasonNames[r]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Reason(%d)", int(r))
+}
+
+// MarshalText returns the textual representation of the reason, so that it is rendered
+// as a string by encoding/json and structured loggers.
+// This implements encoding.TextMarshaler.
+func (r Reason) MarshalText() ([]byte, error) {
+	return []byte(r.String()), nil
+}
@@ -0,0 +1,147 @@
+package feature_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestReason tests the textual representation of reasons.
+func TestReason(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		reason feature.Reason
+		want   string
+	}{
+		{reason: feature.ReasonNotSet, want: "not-set"},
+		{reason: feature.ReasonContextOverride, want: "context-override"},
+		{reason: feature.ReasonDefault, want: "default"},
+		{reason: feature.ReasonRuleMatch, want: "rule-match"},
+		{reason: feature.ReasonRollout, want: "rollout"},
+		{reason: feature.ReasonOverride, want: "override"},
+		{reason: feature.Reason(-1), want: "Reason(-1)"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.reason.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+
+		data, err := json.Marshal(tt.reason)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+
+		if want := fmt.Sprintf("%q", tt.want); string(data) != want {
+			t.Errorf("json.Marshal() = %s, want %s", data, want)
+		}
+	}
+}
+
+// TestInspectionReason tests that inspections report why they have their value.
+func TestInspectionReason(t *testing.T) {
+	t.Parallel()
+
+	registry := feature.NewRegistry()
+	userID := feature.NewNamed[string]("user-id", feature.WithRegistry(registry))
+	region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+	key := feature.NewNamedBool("new-ui",
+		feature.WithRegistry(registry),
+		feature.WithDefault(false),
+		feature.WithRollout(100, userID),
+		feature.WithRules(feature.Rule[bool]{Name: "eu", When: feature.Equals(region, "eu"), Value: true}),
+	)
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantReason feature.Reason
+		wantRule   string
+	}{
+		{
+			name:       "context value",
+			ctx:        key.WithDisabled(region.WithValue(context.Background(), "eu")),
+			wantReason: feature.ReasonContextOverride,
+			wantRule:   "",
+		},
+		{
+			name:       "rule",
+			ctx:        region.WithValue(context.Background(), "eu"),
+			wantReason: feature.ReasonRuleMatch,
+			wantRule:   "eu",
+		},
+		{
+			name:       "rollout",
+			ctx:        userID.WithValue(context.Background(), "user-1"),
+			wantReason: feature.ReasonRollout,
+			wantRule:   "",
+		},
+		{
+			name:       "default",
+			ctx:        context.Background(),
+			wantReason: feature.ReasonDefault,
+			wantRule:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			inspection := key.Inspect(tt.ctx)
+			if inspection.Reason != tt.wantReason || inspection.Rule != tt.wantRule {
+				t.Errorf("Inspect() reason = %v (rule %q), want %v (rule %q)",
+					inspection.Reason, inspection.Rule, tt.wantReason, tt.wantRule)
+			}
+
+			if got := key.InspectBool(tt.ctx).Reason; got != tt.wantReason {
+				t.Errorf("InspectBool().Reason = %v, want %v", got, tt.wantReason)
+			}
+
+			snapshot := registry.Snapshot(tt.ctx)
+
+			last := snapshot[len(snapshot)-1]
+			if last.Reason() != tt.wantReason || last.Rule() != tt.wantRule {
+				t.Errorf("Snapshot() reason = %v (rule %q), want %v (rule %q)",
+					last.Reason(), last.Rule(), tt.wantReason, tt.wantRule)
+			}
+		})
+	}
+
+	t.Run("keys without defaults are not set", func(t *testing.T) {
+		t.Parallel()
+
+		if got := feature.NewNamed[int]("max-items").Inspect(context.Background()).Reason; got != feature.ReasonNotSet {
+			t.Errorf("Inspect().Reason = %v, want %v", got, feature.ReasonNotSet)
+		}
+	})
+}
+
+func ExampleReason() {
+	var (
+		Region   = feature.NewNamed[string]("region")
+		MaxItems = feature.NewNamed[int]("max-items",
+			feature.WithDefault(100),
+			feature.WithRules(feature.Rule[int]{Name: "eu", When: feature.Equals(Region, "eu"), Value: 50}),
+		)
+	)
+
+	ctx := context.Background()
+	fmt.Println(MaxItems.Inspect(ctx).Reason)
+
+	ctx = Region.WithValue(ctx, "eu")
+	fmt.Println(MaxItems.Inspect(ctx).Reason, MaxItems.Inspect(ctx).Rule)
+
+	ctx = MaxItems.WithValue(ctx, 10)
+	fmt.Println(MaxItems.Inspect(ctx).Reason)
+
+	// Output:
+	// default
+	// rule-match eu
+	// context-override
+}
@@ -0,0 +1,221 @@
+package feature
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Registry records feature flag keys so that they can be enumerated and looked up by name.
+//
+// Keys are recorded when they are created with the WithRegistry option.
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	entries []Entry
+	byName  map[string]int
+	opts    *registryOptions
+	hooks   hookSet
+}
+
+// RegistryOption is a function that configures the behavior of a Registry.
+type RegistryOption func(*registryOptions)
+
+// registryOptions configures the behavior of a Registry.
+type registryOptions struct {
+	uniqueNames bool
+}
+
+// WithUniqueNames returns an option that makes the Registry reject keys whose name
+// is already registered.
+//
+// Creating a key with a duplicate name panics with a *DuplicateNameError reporting
+// the call sites of both keys. Since keys are usually package-level variables,
+// the conflict is detected at program initialization.
+//
+// Example:
+//
+//	var registry = feature.NewRegistry(feature.WithUniqueNames())
+//
+//	var A = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+//	var B = feature.NewNamed[int]("max-items", feature.WithRegistry(registry)) // panics
+func WithUniqueNames() RegistryOption {
+	return func(o *registryOptions) {
+		o.uniqueNames = true
+	}
+}
+
+// DuplicateNameError is the panic value reported when a key is registered with a name
+// that is already used by another key in a Registry created with WithUniqueNames.
+type DuplicateNameError struct {
+	// Name is the conflicting key name.
+	Name string
+	// Existing is the call site of the key registered first.
+	Existing CallSite
+	// Duplicate is the call site of the key being registered.
+	Duplicate CallSite
+}
+
+// Error implements the error interface.
+func (e *DuplicateNameError) Error() string {
+	return fmt.Sprintf("key name %q at %s is already registered at %s", e.Name, e.Duplicate, e.Existing)
+}
+
+// Entry describes a key recorded in a Registry.
+type Entry struct {
+	// Key is the registered key.
+	// It can be type-asserted to Key[V] or BoolKey to access values.
+	Key AnyKey
+	// Name is the name of the key, as returned by Key.String().
+	Name string
+	// Type is the value type V of the key.
+	Type reflect.Type
+	// CallSite is the location of the code that created the key.
+	CallSite CallSite
+	// Anonymous is true if the key was created without a name.
+	// Anonymous keys are skipped by name-based sources such as LoadEnv.
+	Anonymous bool
+	// Propagated is true if the key was created with WithPropagation.
+	Propagated bool
+	// Expiry is the date given with WithExpiry, or the zero time if there is none.
+	Expiry time.Time
+	// Owner is the owner given with WithOwner, or empty if there is none.
+	Owner string
+}
+
+// CallSite is a source code location.
+type CallSite struct {
+	// File is the absolute path of the source file.
+	File string
+	// Line is the line number in File.
+	Line int
+}
+
+// IsKnown returns true if the location could be determined.
+func (c CallSite) IsKnown() bool {
+	return c.File != ""
+}
+
+// String returns the location in "<file>:<line>" format.
+// This implements fmt.Stringer.
+func (c CallSite) String() string {
+	if !c.IsKnown() {
+		return "<unknown>"
+	}
+
+	return fmt.Sprintf("%s:%d", c.File, c.Line)
+}
+
+// UnknownKeyError is returned when a name does not match any named key in a Registry.
+type UnknownKeyError struct {
+	// Name is the name that could not be resolved.
+	Name string
+}
+
+// Error implements the error interface.
+func (e *UnknownKeyError) Error() string {
+	return fmt.Sprintf("unknown key %q", e.Name)
+}
+
+//nolint:gochecknoglobals // the default registry is intentionally process-wide
+var defaultRegistry = NewRegistry()
+
+// DefaultRegistry returns the process-wide Registry.
+//
+// Keys are not recorded automatically; pass WithRegistry(feature.DefaultRegistry())
+// when creating a key to record it here.
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// NewRegistry creates a new empty Registry.
+// The Registry can be configured with optional configuration functions.
+func NewRegistry(options ...RegistryOption) *Registry {
+	opts := &registryOptions{
+		uniqueNames: false,
+	}
+	for _, optFn := range options {
+		optFn(opts)
+	}
+
+	return &Registry{
+		mu:      sync.RWMutex{},
+		entries: nil,
+		byName:  make(map[string]int),
+		opts:    opts,
+		hooks:   hookSet{mu: sync.Mutex{}, hooks: atomic.Pointer[[]*hookEntry]{}},
+	}
+}
+
+// register records the entry.
+// It panics with a *DuplicateNameError if the name is already taken and
+// the Registry was created with WithUniqueNames.
+func (r *Registry) register(entry Entry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if idx, exists := r.byName[entry.Name]; !exists {
+		r.byName[entry.Name] = len(r.entries)
+	} else if r.opts.uniqueNames {
+		panic(&DuplicateNameError{
+			Name:      entry.Name,
+			Existing:  r.entries[idx].CallSite,
+			Duplicate: entry.CallSite,
+		})
+	}
+
+	r.entries = append(r.entries, entry)
+}
+
+// Lookup returns the entry of the key with the given name.
+// If several keys share the name, the first registered one is returned.
+func (r *Registry) Lookup(name string) (Entry, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	idx, ok := r.byName[name]
+	if !ok {
+		var zero Entry
+
+		return zero, false
+	}
+
+	return r.entries[idx], true
+}
+
+// lookupNamed returns the entry of the named key with the given name.
+// Anonymous keys are never returned, since their names are derived from call sites.
+func (r *Registry) lookupNamed(name string) (Entry, bool) {
+	entry, ok := r.Lookup(name)
+
+	return entry, ok && !entry.Anonymous
+}
+
+// All returns the entries of all registered keys in registration order.
+// The returned slice is a copy and may be modified freely.
+func (r *Registry) All() []Entry {
+	entries := r.snapshot()
+
+	return append(make([]Entry, 0, len(entries)), entries...)
+}
+
+// Range calls fn for each registered key in registration order.
+// If fn returns false, Range stops the iteration.
+func (r *Registry) Range(fn func(entry Entry) bool) {
+	for _, entry := range r.snapshot() {
+		if !fn(entry) {
+			return
+		}
+	}
+}
+
+// snapshot returns the current entries without copying.
+// Entries are append-only, so the returned slice is never modified afterwards.
+func (r *Registry) snapshot() []Entry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.entries[:len(r.entries):len(r.entries)]
+}
@@ -0,0 +1,358 @@
+package feature_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestRegistry tests recording keys in a Registry.
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("keys without WithRegistry are not recorded", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[int]("unregistered")
+
+		if _, ok := registry.Lookup("unregistered"); ok {
+			t.Error("Lookup() ok = true, want false for unregistered key")
+		}
+
+		if got := len(registry.All()); got != 0 {
+			t.Errorf("len(All()) = %d, want 0", got)
+		}
+	})
+
+	t.Run("Lookup returns registered entry", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		key := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		entry, ok := registry.Lookup("max-items")
+		if !ok {
+			t.Fatal("Lookup() ok = false, want true")
+		}
+
+		if entry.Name != "max-items" {
+			t.Errorf("entry.Name = %q, want %q", entry.Name, "max-items")
+		}
+
+		if entry.Type != reflect.TypeOf(0) {
+			t.Errorf("entry.Type = %v, want int", entry.Type)
+		}
+
+		if got, ok := entry.Key.(feature.Key[int]); !ok || got != key {
+			t.Errorf("entry.Key = %#v, want %#v", entry.Key, key)
+		}
+
+		if got := filepath.Base(entry.CallSite.File); got != "registry_test.go" {
+			t.Errorf("filepath.Base(entry.CallSite.File) = %q, want %q", got, "registry_test.go")
+		}
+
+		if entry.CallSite.Line != 36 {
+			t.Errorf("entry.CallSite.Line = %d, want 36", entry.CallSite.Line)
+		}
+	})
+
+	t.Run("Lookup returns false for unknown name", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+
+		if _, ok := registry.Lookup("unknown"); ok {
+			t.Error("Lookup() ok = true, want false")
+		}
+	})
+
+	t.Run("bool keys are recorded as BoolKey", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		flag := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+
+		entry, ok := registry.Lookup("new-ui")
+		if !ok {
+			t.Fatal("Lookup() ok = false, want true")
+		}
+
+		if got, ok := entry.Key.(feature.BoolKey); !ok || got != flag {
+			t.Errorf("entry.Key = %#v, want %#v", entry.Key, flag)
+		}
+
+		if entry.Type != reflect.TypeOf(false) {
+			t.Errorf("entry.Type = %v, want bool", entry.Type)
+		}
+
+		if entry.CallSite.Line != 78 {
+			t.Errorf("entry.CallSite.Line = %d, want 78", entry.CallSite.Line)
+		}
+	})
+
+	t.Run("anonymous keys are recorded with call site", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		key := feature.New[string](feature.WithRegistry(registry))
+
+		entries := registry.All()
+		if len(entries) != 1 {
+			t.Fatalf("len(All()) = %d, want 1", len(entries))
+		}
+
+		if entries[0].Name != key.String() {
+			t.Errorf("entry.Name = %q, want %q", entries[0].Name, key.String())
+		}
+
+		assertAnonymousKeyFormat(t, entries[0].Name, "registry_test.go", 102)
+	})
+
+	t.Run("interface value types are preserved", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[fmt.Stringer]("stringer", feature.WithRegistry(registry))
+
+		entry, _ := registry.Lookup("stringer")
+
+		want := reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+		if entry.Type != want {
+			t.Errorf("entry.Type = %v, want %v", entry.Type, want)
+		}
+	})
+
+	t.Run("All and Range preserve registration order", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamedBool("first", feature.WithRegistry(registry))
+		_ = feature.NewNamed[int]("second", feature.WithRegistry(registry))
+		_ = feature.NewNamed[string]("third", feature.WithRegistry(registry))
+
+		var ranged []string
+
+		registry.Range(func(entry feature.Entry) bool {
+			ranged = append(ranged, entry.Name)
+
+			return true
+		})
+
+		all := make([]string, 0, 3)
+		for _, entry := range registry.All() {
+			all = append(all, entry.Name)
+		}
+
+		want := []string{"first", "second", "third"}
+		if !reflect.DeepEqual(ranged, want) {
+			t.Errorf("Range() visited %v, want %v", ranged, want)
+		}
+
+		if !reflect.DeepEqual(all, want) {
+			t.Errorf("All() = %v, want %v", all, want)
+		}
+	})
+
+	t.Run("Range stops when fn returns false", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamedBool("first", feature.WithRegistry(registry))
+		_ = feature.NewNamedBool("second", feature.WithRegistry(registry))
+
+		var count int
+
+		registry.Range(func(feature.Entry) bool {
+			count++
+
+			return false
+		})
+
+		if count != 1 {
+			t.Errorf("Range() visited %d entries, want 1", count)
+		}
+	})
+
+	t.Run("Lookup returns first key for duplicate names", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		first := feature.NewNamed[int]("dup", feature.WithRegistry(registry))
+		_ = feature.NewNamed[int]("dup", feature.WithRegistry(registry))
+
+		entry, _ := registry.Lookup("dup")
+		if entry.Key != first {
+			t.Errorf("Lookup() returned %v, want the first registered key", entry.CallSite)
+		}
+
+		if got := len(registry.All()); got != 2 {
+			t.Errorf("len(All()) = %d, want 2", got)
+		}
+	})
+
+	t.Run("DefaultRegistry returns the same instance", func(t *testing.T) {
+		t.Parallel()
+
+		if feature.DefaultRegistry() != feature.DefaultRegistry() {
+			t.Error("DefaultRegistry() returned different instances")
+		}
+	})
+}
+
+// TestRegistryUniqueNames tests duplicate-name detection.
+func TestRegistryUniqueNames(t *testing.T) {
+	t.Parallel()
+
+	t.Run("duplicate name panics with both call sites", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry(feature.WithUniqueNames())
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("NewNamed() did not panic for duplicate name")
+			}
+
+			err, ok := r.(error)
+			if !ok {
+				t.Fatalf("panic value = %#v, want error", r)
+			}
+
+			var dupErr *feature.DuplicateNameError
+			if !errors.As(err, &dupErr) {
+				t.Fatalf("panic value = %#v, want *feature.DuplicateNameError", err)
+			}
+
+			if dupErr.Name != "max-items" {
+				t.Errorf("Name = %q, want %q", dupErr.Name, "max-items")
+			}
+
+			if dupErr.Existing.Line != 215 {
+				t.Errorf("Existing.Line = %d, want 215", dupErr.Existing.Line)
+			}
+
+			if dupErr.Duplicate.Line != 250 {
+				t.Errorf("Duplicate.Line = %d, want 250", dupErr.Duplicate.Line)
+			}
+
+			checkContains(t, err.Error(), `key name "max-items"`)
+			checkContains(t, err.Error(), "registry_test.go:215")
+			checkContains(t, err.Error(), "registry_test.go:250")
+		}()
+
+		_ = feature.NewNamedBool("max-items", feature.WithRegistry(registry))
+	})
+
+	t.Run("distinct names are accepted", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry(feature.WithUniqueNames())
+		_ = feature.NewNamed[int]("a", feature.WithRegistry(registry))
+		_ = feature.NewNamed[int]("b", feature.WithRegistry(registry))
+
+		if got := len(registry.All()); got != 2 {
+			t.Errorf("len(All()) = %d, want 2", got)
+		}
+	})
+
+	t.Run("anonymous keys never conflict", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry(feature.WithUniqueNames())
+
+		for idx := 0; idx < 3; idx++ {
+			_ = feature.NewBool(feature.WithRegistry(registry))
+		}
+
+		if got := len(registry.All()); got != 3 {
+			t.Errorf("len(All()) = %d, want 3", got)
+		}
+	})
+
+	t.Run("same name in different registries is accepted", func(t *testing.T) {
+		t.Parallel()
+
+		registryA := feature.NewRegistry(feature.WithUniqueNames())
+		registryB := feature.NewRegistry(feature.WithUniqueNames())
+		_ = feature.NewNamed[int]("shared", feature.WithRegistry(registryA))
+		_ = feature.NewNamed[int]("shared", feature.WithRegistry(registryB))
+
+		if _, ok := registryB.Lookup("shared"); !ok {
+			t.Error("Lookup() ok = false, want true")
+		}
+	})
+}
+
+// TestCallSite tests CallSite formatting.
+func TestCallSite(t *testing.T) {
+	t.Parallel()
+
+	t.Run("known call site", func(t *testing.T) {
+		t.Parallel()
+
+		site := feature.CallSite{File: "/path/to/file.go", Line: 42}
+
+		if !site.IsKnown() {
+			t.Error("IsKnown() = false, want true")
+		}
+
+		if got, want := site.String(), "/path/to/file.go:42"; got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("unknown call site", func(t *testing.T) {
+		t.Parallel()
+
+		var site feature.CallSite
+
+		if site.IsKnown() {
+			t.Error("IsKnown() = true, want false")
+		}
+
+		if got, want := site.String(), "<unknown>"; got != want {
+			t.Errorf("String() = %q, want %q", got, want)
+		}
+	})
+}
+
+func ExampleRegistry() {
+	registry := feature.NewRegistry()
+
+	// Record keys by passing WithRegistry when creating them
+	var (
+		_ = feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+	)
+
+	for _, entry := range registry.All() {
+		fmt.Printf("%s (%s)\n", entry.Name, entry.Type)
+	}
+
+	// Output:
+	// new-ui (bool)
+	// max-items (int)
+}
+
+func ExampleRegistry_Lookup() {
+	registry := feature.NewRegistry()
+
+	var MaxItems = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+	entry, ok := registry.Lookup("max-items")
+	fmt.Println(ok, entry.Key == MaxItems)
+
+	_, ok = registry.Lookup("unknown")
+	fmt.Println(ok)
+
+	// Output:
+	// true true
+	// false
+}
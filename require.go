@@ -0,0 +1,139 @@
+package feature
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Requirements is a set of keys that must be set in a context, created with Require.
+type Requirements struct {
+	keys []AnyKey
+}
+
+// MissingKeysError is returned by Requirements.Validate when required keys are not set.
+// It wraps a *NotSetError for every missing key, so it matches ErrNotSet with errors.Is.
+type MissingKeysError struct {
+	// Errors holds a *NotSetError for every missing key, in the order the keys were required.
+	Errors []*NotSetError
+}
+
+// Error implements the error interface.
+func (e *MissingKeysError) Error() string {
+	names := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		names = append(names, err.Key)
+	}
+
+	return "required keys are not set in context: " + strings.Join(names, ", ")
+}
+
+// Unwrap returns the *NotSetError of every missing key.
+func (e *MissingKeysError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+
+	return errs
+}
+
+// Require returns the set of the given keys, to validate once at the edge that a context
+// carries every key a handler depends on, instead of calling MustGet deep in the stack.
+//
+// A key is satisfied only if it is set in the context or forced with Override;
+// default values, rules and rollouts are not considered, as for MustGet.
+//
+// Example:
+//
+//	var checkoutRequirements = feature.Require(MaxItems, TenantID, EnableNewUI)
+//
+//	if err := checkoutRequirements.Validate(ctx); err != nil {
+//	    return err // required keys are not set in context: max-items, tenant-id
+//	}
+func Require(keys ...AnyKey) *Requirements {
+	return &Requirements{keys: keys}
+}
+
+// Validate returns a *MissingKeysError listing every required key that is not set in the context,
+// or nil if all of them are set. Only the context and the overrides are consulted:
+// rules and rollouts are not evaluated, and hooks are not called.
+func (r *Requirements) Validate(ctx context.Context) error {
+	var errs []*NotSetError
+
+	for _, key := range r.keys {
+		if _, ok := key.tryGetAny(ctx); !ok {
+			errs = append(errs, &NotSetError{Key: key.String(), Type: key.valueType()})
+		}
+	}
+
+	if len(errs) > 0 {
+		return &MissingKeysError{Errors: errs}
+	}
+
+	return nil
+}
+
+// RequireOption is a function that configures the behavior of the Requirements middleware.
+type RequireOption func(*requireOptions)
+
+// requireOptions configures the behavior of the Requirements middleware.
+type requireOptions struct {
+	onError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// WithRequireErrorHandler returns an option that sets the response written when required keys
+// are missing from the request context. The handler receives the *MissingKeysError.
+// Without this option, the middleware responds with 400 Bad Request and the error message.
+//
+// Example:
+//
+//	feature.WithRequireErrorHandler(func(w http.ResponseWriter, r *http.Request, err error) {
+//	    slog.ErrorContext(r.Context(), "missing feature flags", "error", err)
+//	    http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+//	})
+func WithRequireErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err error)) RequireOption {
+	return func(o *requireOptions) {
+		o.onError = handler
+	}
+}
+
+// requireOptionsFrom applies the given option functions to create a configured requireOptions.
+func requireOptionsFrom(options []RequireOption) *requireOptions {
+	opts := &requireOptions{
+		onError: func(w http.ResponseWriter, _ *http.Request, err error) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		},
+	}
+	for _, optFn := range options {
+		optFn(opts)
+	}
+
+	return opts
+}
+
+// Middleware returns HTTP middleware that validates the request context against the
+// Requirements and rejects the request without calling the next handler if keys are missing.
+//
+// Install it after the middleware that sets the values, such as Registry.Middleware.
+//
+// Example:
+//
+//	handler := registry.Middleware()(
+//	    feature.Require(MaxItems, TenantID).Middleware()(checkoutHandler),
+//	)
+func (r *Requirements) Middleware(options ...RequireOption) func(http.Handler) http.Handler {
+	opts := requireOptionsFrom(options)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if err := r.Validate(req.Context()); err != nil {
+				opts.onError(w, req, err)
+
+				return
+			}
+
+			next.ServeHTTP(w, req)
+		})
+	}
+}
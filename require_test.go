@@ -0,0 +1,193 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestRequirements tests validating a context against a set of required keys.
+func TestRequirements(t *testing.T) {
+	t.Parallel()
+
+	t.Run("all keys set", func(t *testing.T) {
+		t.Parallel()
+
+		maxItems := feature.NewNamed[int]("max-items")
+		newUI := feature.NewNamedBool("new-ui")
+
+		ctx := feature.With(context.Background(), maxItems.Bind(100), newUI.BindDisabled())
+
+		if err := feature.Require(maxItems, newUI).Validate(ctx); err != nil {
+			t.Errorf("Validate() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("missing keys are aggregated", func(t *testing.T) {
+		t.Parallel()
+
+		maxItems := feature.NewNamed[int]("max-items", feature.WithDefault(100))
+		tenantID := feature.NewNamed[string]("tenant-id")
+		newUI := feature.NewNamedBool("new-ui")
+
+		ctx := newUI.WithEnabled(context.Background())
+
+		err := feature.Require(maxItems, tenantID, newUI).Validate(ctx)
+
+		want := "required keys are not set in context: max-items, tenant-id"
+		if fmt.Sprint(err) != want {
+			t.Errorf("Validate() error = %v, want %q", err, want)
+		}
+
+		if !errors.Is(err, feature.ErrNotSet) {
+			t.Errorf("errors.Is(%v, ErrNotSet) = false, want true", err)
+		}
+
+		var missing *feature.MissingKeysError
+		if !errors.As(err, &missing) || len(missing.Errors) != 2 {
+			t.Fatalf("Validate() error = %v, want *feature.MissingKeysError with 2 errors", err)
+		}
+
+		if got := missing.Errors[1].Type.String(); got != "string" {
+			t.Errorf("Errors[1].Type = %s, want string", got)
+		}
+
+		var notSet *feature.NotSetError
+		if !errors.As(err, &notSet) || notSet.Key != "max-items" {
+			t.Errorf("errors.As(%v, *NotSetError) = %v, want max-items", err, notSet)
+		}
+	})
+
+	t.Run("overridden keys are set", func(t *testing.T) {
+		t.Parallel()
+
+		retries := feature.NewNamed[int]("retries")
+
+		restore := feature.Override(retries, 3)
+		defer restore()
+
+		if err := feature.Require(retries).Validate(context.Background()); err != nil {
+			t.Errorf("Validate() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("computed values do not count and hooks are not called", func(t *testing.T) {
+		t.Parallel()
+
+		var calls int
+
+		registry := feature.NewRegistry()
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithRules(
+			feature.Rule[int]{Name: "eu", When: feature.Equals(region, "eu"), Value: 50},
+		))
+
+		defer registry.AddHook(func(context.Context, feature.AnyInspection) { calls++ })()
+
+		err := feature.Require(region, maxItems).Validate(region.WithValue(context.Background(), "eu"))
+		if want := "required keys are not set in context: max-items"; fmt.Sprint(err) != want {
+			t.Errorf("Validate() error = %v, want %q", err, want)
+		}
+
+		if calls != 0 {
+			t.Errorf("hook called %d times, want 0", calls)
+		}
+	})
+}
+
+// TestRequirementsMiddleware tests rejecting requests that lack required keys.
+func TestRequirementsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	maxItems := feature.NewNamed[int]("max-items")
+	requirements := feature.Require(maxItems)
+
+	t.Run("passes requests with all keys set", func(t *testing.T) {
+		t.Parallel()
+
+		var called bool
+
+		handler := requirements.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			called = true
+		}))
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req = req.WithContext(maxItems.WithValue(req.Context(), 100))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if !called || rec.Code != http.StatusOK {
+			t.Errorf("called = %v, status = %d, want true, %d", called, rec.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("rejects requests with bad request by default", func(t *testing.T) {
+		t.Parallel()
+
+		handler := requirements.Middleware()(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			t.Error("next handler called, want rejection")
+		}))
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+
+		checkContains(t, rec.Body.String(), "max-items")
+	})
+
+	t.Run("custom error handler", func(t *testing.T) {
+		t.Parallel()
+
+		var got error
+
+		onError := func(w http.ResponseWriter, _ *http.Request, err error) {
+			got = err
+
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		next := http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			t.Error("next handler called, want rejection")
+		})
+		handler := requirements.Middleware(feature.WithRequireErrorHandler(onError))(next)
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+		if rec.Code != http.StatusServiceUnavailable {
+			t.Errorf("status = %d, want %d", rec.Code, http.StatusServiceUnavailable)
+		}
+
+		if !errors.Is(got, feature.ErrNotSet) {
+			t.Errorf("error handler got %v, want ErrNotSet", got)
+		}
+	})
+}
+
+func ExampleRequire() {
+	var (
+		MaxItems    = feature.NewNamed[int]("max-items")
+		TenantID    = feature.NewNamed[string]("tenant-id")
+		EnableNewUI = feature.NewNamedBool("new-ui")
+	)
+
+	requirements := feature.Require(MaxItems, TenantID, EnableNewUI)
+
+	ctx := EnableNewUI.WithEnabled(context.Background())
+	fmt.Println(requirements.Validate(ctx))
+
+	ctx = feature.With(ctx, MaxItems.Bind(100), TenantID.Bind("acme"))
+	fmt.Println(requirements.Validate(ctx))
+
+	// Output:
+	// required keys are not set in context: max-items, tenant-id
+	// <nil>
+}
@@ -0,0 +1,93 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+)
+
+// rolloutBuckets is the number of buckets identifiers are hashed into,
+// giving percentages a resolution of 0.01.
+const rolloutBuckets = 10000
+
+// rollout enables a bool key for a stable fraction of identifiers.
+type rollout struct {
+	percentage float64
+	bucketBy   Key[string]
+}
+
+// WithRollout returns an option that enables a bool key for the given percentage of
+// identifiers when it is not set in the context.
+//
+// The identifier is read from bucketBy, e.g. a key holding the user ID. Each identifier is
+// hashed together with the key name into one of 10000 buckets, so the same identifier always
+// gets the same result for the same key across processes and versions, while different keys
+// roll out to independent populations. Increasing the percentage only ever adds identifiers.
+//
+// Values set in the context, e.g. with WithEnabled or WithDisabled, always win over the rollout.
+// If bucketBy is not set or empty, the key falls back to its default value.
+// As with WithDefault, TryGet, IsSet and Inspection.Ok still report whether the key
+// was actually set in the context.
+//
+// The key must be a named bool key and percentage must be within [0, 100];
+// otherwise creating the key panics.
+//
+// Example:
+//
+//	var UserID = feature.NewNamed[string]("user-id")
+//	var NewCheckout = feature.NewNamedBool("new-checkout", feature.WithRollout(25, UserID))
+//
+//	ctx = UserID.WithValue(ctx, "42")
+//	fmt.Println(NewCheckout.Enabled(ctx)) // Output: true for 25% of user IDs
+func WithRollout(percentage float64, bucketBy Key[string]) Option {
+	return func(o *options) {
+		o.rollout = &rollout{percentage: percentage, bucketBy: bucketBy}
+	}
+}
+
+// rolloutFrom validates the rollout collected in options against the key.
+func rolloutFrom[V any](opts *options, name string) *rollout {
+	if opts.rollout == nil {
+		return nil
+	}
+
+	if typeOf[V]() != typeOf[bool]() {
+		panic(fmt.Sprintf("rollout is not applicable to key %s of type %s", name, typeOf[V]()))
+	}
+
+	if opts.name == "" {
+		panic(fmt.Sprintf("rollout requires a named key, got %s", name))
+	}
+
+	if opts.rollout.bucketBy == nil {
+		panic(fmt.Sprintf("rollout of key %s requires a bucketing key", name))
+	}
+
+	if !(opts.rollout.percentage >= 0 && opts.rollout.percentage <= 100) {
+		panic(fmt.Sprintf("rollout percentage %v of key %s is out of range [0, 100]", opts.rollout.percentage, name))
+	}
+
+	return opts.rollout
+}
+
+// evaluate reports whether the key is rolled out for the identifier set in the context.
+// It returns false as the second value if no identifier is available.
+func (r *rollout) evaluate(ctx context.Context, name string) (bool, bool) {
+	id, _, ok := r.bucketBy.downcast().lookup(ctx)
+	if !ok || id == "" {
+		return false, false
+	}
+
+	return float64(rolloutBucket(name, id)) < r.percentage*rolloutBuckets/100, true
+}
+
+// rolloutBucket deterministically hashes the identifier into a bucket in [0, rolloutBuckets).
+// It uses 32-bit FNV-1a, whose output is fixed by its specification, over "<name>:<id>".
+func rolloutBucket(name, id string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(name))
+	_, _ = h.Write([]byte{':'})
+	_, _ = h.Write([]byte(id))
+
+	return h.Sum32() % rolloutBuckets
+}
@@ -0,0 +1,179 @@
+package feature_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestWithRollout tests percentage rollouts of bool keys.
+func TestWithRollout(t *testing.T) {
+	t.Parallel()
+
+	t.Run("bucketing is stable across processes and versions", func(t *testing.T) {
+		t.Parallel()
+
+		userID := feature.NewNamed[string]("user-id")
+		key := feature.NewNamedBool("new-checkout", feature.WithRollout(50, userID))
+
+		// Golden values: changing them moves users between buckets.
+		want := map[string]bool{
+			"user-1": true, "user-2": true, "user-3": false, "user-4": false, "user-5": true,
+			"user-6": true, "user-7": false, "user-8": false, "user-9": false, "user-10": true,
+		}
+
+		for id, enabled := range want {
+			if got := key.Enabled(userID.WithValue(context.Background(), id)); got != enabled {
+				t.Errorf("Enabled() for %s = %v, want %v", id, got, enabled)
+			}
+		}
+	})
+
+	t.Run("percentage bounds", func(t *testing.T) {
+		t.Parallel()
+
+		userID := feature.NewNamed[string]("user-id")
+		none := feature.NewNamedBool("none", feature.WithRollout(0, userID))
+		all := feature.NewNamedBool("all", feature.WithRollout(100, userID))
+		half := feature.NewNamedBool("half", feature.WithRollout(50, userID))
+
+		enabled := 0
+
+		for idx := 0; idx < 1000; idx++ {
+			ctx := userID.WithValue(context.Background(), fmt.Sprint(idx))
+
+			if none.Enabled(ctx) {
+				t.Fatalf("none.Enabled() = true for %d, want false", idx)
+			}
+
+			if !all.Enabled(ctx) {
+				t.Fatalf("all.Enabled() = false for %d, want true", idx)
+			}
+
+			if half.Enabled(ctx) {
+				enabled++
+			}
+		}
+
+		if enabled < 400 || enabled > 600 {
+			t.Errorf("half.Enabled() = true for %d of 1000 identifiers, want about 500", enabled)
+		}
+	})
+
+	t.Run("context values win over the rollout", func(t *testing.T) {
+		t.Parallel()
+
+		userID := feature.NewNamed[string]("user-id")
+		key := feature.NewNamedBool("new-checkout", feature.WithRollout(100, userID))
+		ctx := userID.WithValue(context.Background(), "user-1")
+
+		if !key.Enabled(ctx) || key.IsSet(ctx) {
+			t.Errorf("Enabled(), IsSet() = %v, %v, want true, false", key.Enabled(ctx), key.IsSet(ctx))
+		}
+
+		if got := key.Inspect(ctx).String(); got != "new-checkout: true (rollout)" {
+			t.Errorf("Inspect().String() = %q, want %q", got, "new-checkout: true (rollout)")
+		}
+
+		if _, ok := key.TryGet(ctx); ok {
+			t.Error("TryGet() ok = true, want false for rolled out value")
+		}
+
+		ctx = key.WithDisabled(ctx)
+		if !key.ExplicitlyDisabled(ctx) || key.Enabled(ctx) {
+			t.Error("WithDisabled() did not override the rollout")
+		}
+	})
+
+	t.Run("missing identifiers fall back to the default", func(t *testing.T) {
+		t.Parallel()
+
+		userID := feature.NewNamed[string]("user-id")
+		key := feature.NewNamedBool("new-checkout", feature.WithRollout(0, userID), feature.WithDefault(true))
+
+		if !key.Enabled(context.Background()) {
+			t.Error("Enabled() = false, want default value true")
+		}
+
+		if !key.Enabled(userID.WithValue(context.Background(), "")) {
+			t.Error("Enabled() = false, want default value true for empty identifier")
+		}
+
+		if key.Enabled(userID.WithValue(context.Background(), "user-1")) {
+			t.Error("Enabled() = true, want rollout to win over the default")
+		}
+	})
+
+	t.Run("invalid configurations panic", func(t *testing.T) {
+		t.Parallel()
+
+		userID := feature.NewNamed[string]("user-id")
+
+		tests := []struct {
+			name string
+			new  func()
+			want string
+		}{
+			{
+				name: "non-bool key",
+				new:  func() { _ = feature.NewNamed[int]("max-items", feature.WithRollout(50, userID)) },
+				want: "rollout is not applicable to key max-items of type int",
+			},
+			{
+				name: "anonymous key",
+				new:  func() { _ = feature.NewBool(feature.WithRollout(50, userID)) },
+				want: "rollout requires a named key",
+			},
+			{
+				name: "missing bucketing key",
+				new:  func() { _ = feature.NewNamedBool("new-checkout", feature.WithRollout(50, nil)) },
+				want: "rollout of key new-checkout requires a bucketing key",
+			},
+			{
+				name: "out of range percentage",
+				new:  func() { _ = feature.NewNamedBool("new-checkout", feature.WithRollout(101, userID)) },
+				want: "rollout percentage 101 of key new-checkout is out of range [0, 100]",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				defer func() {
+					r := recover()
+					if r == nil {
+						t.Fatal("constructor did not panic")
+					}
+
+					checkContains(t, fmt.Sprint(r), tt.want)
+				}()
+
+				tt.new()
+			})
+		}
+	})
+}
+
+func ExampleWithRollout() {
+	var (
+		UserID      = feature.NewNamed[string]("user-id")
+		NewCheckout = feature.NewNamedBool("new-checkout", feature.WithRollout(50, UserID))
+	)
+
+	for _, id := range []string{"user-1", "user-3"} {
+		ctx := UserID.WithValue(context.Background(), id)
+		fmt.Println(id, NewCheckout.Inspect(ctx))
+	}
+
+	ctx := NewCheckout.WithEnabled(UserID.WithValue(context.Background(), "user-3"))
+	fmt.Println("user-3", NewCheckout.Inspect(ctx))
+
+	// Output:
+	// user-1 new-checkout: true (rollout)
+	// user-3 new-checkout: false (rollout)
+	// user-3 new-checkout: true
+}
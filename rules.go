@@ -0,0 +1,312 @@
+package feature
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrMalformedRule is returned by ParseRules when a rule or condition cannot be parsed.
+var ErrMalformedRule = errors.New("malformed rule")
+
+// Condition reports whether a Rule applies to a context.
+type Condition func(ctx context.Context) bool
+
+// Equals returns a condition that holds if the key is set to the value in the context.
+// The key is read like TryGet, so default values and computed values of the key are not considered,
+// but without calling its hooks, since evaluating a condition is not a use of the key.
+func Equals[V comparable](key Key[V], value V) Condition {
+	return func(ctx context.Context) bool {
+		got, _, ok := key.downcast().lookup(ctx)
+
+		return ok && got == value
+	}
+}
+
+// All returns a condition that holds if all the conditions hold.
+// It holds if no conditions are given.
+func All(conditions ...Condition) Condition {
+	return func(ctx context.Context) bool {
+		for _, cond := range conditions {
+			if !cond(ctx) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any returns a condition that holds if any of the conditions holds.
+// It does not hold if no conditions are given.
+func Any(conditions ...Condition) Condition {
+	return func(ctx context.Context) bool {
+		for _, cond := range conditions {
+			if cond(ctx) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not returns a condition that holds if the condition does not hold.
+func Not(condition Condition) Condition {
+	return func(ctx context.Context) bool {
+		return !condition(ctx)
+	}
+}
+
+// Rule computes the value of a key from other keys in the same context.
+type Rule[V any] struct {
+	// Name identifies the rule. It is reported in Inspection.Rule when the rule matches.
+	Name string
+	// When is the condition under which the rule applies.
+	// A nil condition always holds, which is useful for a catch-all last rule.
+	When Condition
+	// Value is the value of the key when the rule applies.
+	Value V
+}
+
+// WithRules returns an option that computes the value of the key from ordered rules
+// when it is not set in the context.
+//
+// Rules are evaluated in the order they are given, and the value of the first rule whose
+// condition holds is used; if no rule matches, the key falls back to its rollout
+// (see WithRollout) and default value. Values set in the context always win over rules.
+// The name of the matched rule is reported in Inspection.Rule.
+// As with WithDefault, TryGet, IsSet and Inspection.Ok still report whether the key
+// was actually set in the context.
+//
+// The rules must have exactly the value type of the key and non-empty names, and their
+// values must pass the validators of the key; otherwise creating the key panics.
+//
+// Example:
+//
+//	var (
+//	    Region = feature.NewNamed[string]("region")
+//	    Plan   = feature.NewNamed[string]("plan")
+//	    NewUI  = feature.NewNamedBool("new-ui", feature.WithRules(feature.Rule[bool]{
+//	        Name:  "eu-pro",
+//	        When:  feature.All(feature.Equals(Region, "eu"), feature.Equals(Plan, "pro")),
+//	        Value: true,
+//	    }))
+//	)
+func WithRules[V any](rules ...Rule[V]) Option {
+	return func(o *options) {
+		for _, rule := range rules {
+			o.rules = append(o.rules, rule)
+		}
+	}
+}
+
+// rulesFrom converts the rules collected in options to the value type of the key.
+func rulesFrom[V any](name string, rules []any) []Rule[V] {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	typed := make([]Rule[V], 0, len(rules))
+
+	for _, rule := range rules {
+		r, ok := rule.(Rule[V])
+		if !ok {
+			panic(fmt.Sprintf("rule of type %T is not applicable to key %s of type %s",
+				rule, name, typeOf[V]()))
+		}
+
+		if r.Name == "" {
+			panic(fmt.Sprintf("rule of key %s must have a name", name))
+		}
+
+		typed = append(typed, r)
+	}
+
+	return typed
+}
+
+// evaluateRules returns the value and the name of the first matching rule.
+// It returns false if no rule matches.
+func (k key[V]) evaluateRules(ctx context.Context) (V, string, bool) {
+	for _, rule := range k.rules {
+		if rule.When == nil || rule.When(ctx) {
+			return rule.Value, rule.Name, true
+		}
+	}
+
+	var zero V
+
+	return zero, "", false
+}
+
+// ruleDocument is the JSON representation of a Rule.
+type ruleDocument struct {
+	Name  string             `json:"name"`
+	When  *conditionDocument `json:"when"`
+	Value json.RawMessage    `json:"value"`
+}
+
+// conditionDocument is the JSON representation of a Condition.
+// Exactly one of the forms {"key", "equals"}, "all", "any" or "not" must be present.
+type conditionDocument struct {
+	Key    string               `json:"key"`
+	Equals json.RawMessage      `json:"equals"`
+	All    []*conditionDocument `json:"all"`
+	Any    []*conditionDocument `json:"any"`
+	Not    *conditionDocument   `json:"not"`
+}
+
+// ParseRules parses rules from a JSON document for use with WithRules.
+//
+// The document is a JSON array of rules; conditions refer to other keys by name,
+// which are resolved against the named keys recorded in the Registry:
+//
+//	[
+//	  {
+//	    "name": "eu-pro",
+//	    "when": {"all": [{"key": "region", "equals": "eu"}, {"key": "plan", "equals": "pro"}]},
+//	    "value": true
+//	  },
+//	  {"name": "beta-testers", "when": {"not": {"key": "beta", "equals": false}}, "value": true}
+//	]
+//
+// A condition is one of {"key": <name>, "equals": <value>}, {"all": [...]}, {"any": [...]}
+// or {"not": {...}}, with the same meaning as Equals, All, Any and Not. A rule without
+// "when" always applies. Values are decoded with encoding/json into the value type of the
+// referenced key or of the rule, so they are type-checked.
+//
+// ParseRules returns an error joining an ErrMalformedRule, *UnknownKeyError or *ParseError
+// for each offending rule.
+//
+// Example:
+//
+//	rules, err := feature.ParseRules[bool](registry, data)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	var NewUI = feature.NewNamedBool("new-ui", feature.WithRules(rules...))
+func ParseRules[V any](registry *Registry, data []byte) ([]Rule[V], error) {
+	var docs []ruleDocument
+	if err := json.Unmarshal(data, &docs); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrMalformedRule, err)
+	}
+
+	rules := make([]Rule[V], 0, len(docs))
+
+	var errs []error
+
+	for idx, doc := range docs {
+		rule, err := parseRule[V](registry, doc)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("rule #%d: %w", idx, err))
+
+			continue
+		}
+
+		rules = append(rules, rule)
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// parseRule converts a single rule document.
+func parseRule[V any](registry *Registry, doc ruleDocument) (Rule[V], error) {
+	var rule Rule[V]
+
+	if doc.Name == "" {
+		return rule, fmt.Errorf("%w: missing name", ErrMalformedRule)
+	}
+
+	if len(doc.Value) == 0 {
+		return rule, fmt.Errorf("%w: missing value of rule %s", ErrMalformedRule, doc.Name)
+	}
+
+	if err := json.Unmarshal(doc.Value, &rule.Value); err != nil {
+		return rule, fmt.Errorf("%w: value of rule %s: %w", ErrMalformedRule, doc.Name, err)
+	}
+
+	rule.Name = doc.Name
+
+	if doc.When != nil {
+		cond, err := registry.parseCondition(doc.When)
+		if err != nil {
+			return rule, fmt.Errorf("rule %s: %w", doc.Name, err)
+		}
+
+		rule.When = cond
+	}
+
+	return rule, nil
+}
+
+// parseCondition converts a condition document.
+func (r *Registry) parseCondition(doc *conditionDocument) (Condition, error) {
+	switch {
+	case doc == nil:
+		return nil, fmt.Errorf("%w: null condition", ErrMalformedRule)
+	case doc.Key != "" && doc.All == nil && doc.Any == nil && doc.Not == nil:
+		return r.parseEquals(doc)
+	case doc.Key == "" && doc.All != nil && doc.Any == nil && doc.Not == nil:
+		conds, err := r.parseConditions(doc.All)
+
+		return All(conds...), err
+	case doc.Key == "" && doc.All == nil && doc.Any != nil && doc.Not == nil:
+		conds, err := r.parseConditions(doc.Any)
+
+		return Any(conds...), err
+	case doc.Key == "" && doc.All == nil && doc.Any == nil && doc.Not != nil:
+		cond, err := r.parseCondition(doc.Not)
+
+		return Not(cond), err
+	default:
+		return nil, fmt.Errorf(`%w: condition must have exactly one of "key", "all", "any" or "not"`, ErrMalformedRule)
+	}
+}
+
+// parseConditions converts a list of condition documents.
+func (r *Registry) parseConditions(docs []*conditionDocument) ([]Condition, error) {
+	conds := make([]Condition, 0, len(docs))
+
+	for _, doc := range docs {
+		cond, err := r.parseCondition(doc)
+		if err != nil {
+			return nil, err
+		}
+
+		conds = append(conds, cond)
+	}
+
+	return conds, nil
+}
+
+// parseEquals converts a {"key", "equals"} condition document.
+func (r *Registry) parseEquals(doc *conditionDocument) (Condition, error) {
+	entry, ok := r.lookupNamed(doc.Key)
+	if !ok {
+		return nil, &UnknownKeyError{Name: doc.Key}
+	}
+
+	if len(doc.Equals) == 0 || bytes.Equal(doc.Equals, []byte("null")) {
+		return nil, fmt.Errorf("%w: missing value to compare key %s with", ErrMalformedRule, doc.Key)
+	}
+
+	want, err := entry.Key.decodeAny(doc.Equals)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context) bool {
+		got, ok := entry.Key.tryGetAny(ctx)
+
+		return ok && reflect.DeepEqual(got, want)
+	}, nil
+}
@@ -0,0 +1,319 @@
+package feature_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestWithRules tests computing values from ordered rules.
+func TestWithRules(t *testing.T) {
+	t.Parallel()
+
+	region := feature.NewNamed[string]("region")
+	plan := feature.NewNamed[string]("plan")
+
+	newKey := func() feature.Key[int] {
+		return feature.NewNamed[int]("max-items",
+			feature.WithDefault(10),
+			feature.WithRules(
+				feature.Rule[int]{
+					Name:  "eu-pro",
+					When:  feature.All(feature.Equals(region, "eu"), feature.Equals(plan, "pro")),
+					Value: 1000,
+				},
+				feature.Rule[int]{
+					Name:  "pro",
+					When:  feature.Any(feature.Equals(plan, "pro"), feature.Equals(plan, "enterprise")),
+					Value: 100,
+				},
+				feature.Rule[int]{
+					Name:  "not-us",
+					When:  feature.Not(feature.Equals(region, "us")),
+					Value: 50,
+				},
+			),
+		)
+	}
+
+	tests := []struct {
+		name     string
+		region   string
+		plan     string
+		want     int
+		wantRule string
+	}{
+		{name: "first matching rule wins", region: "eu", plan: "pro", want: 1000, wantRule: "eu-pro"},
+		{name: "later rule matches", region: "us", plan: "enterprise", want: 100, wantRule: "pro"},
+		{name: "unset keys do not equal anything", region: "", plan: "", want: 50, wantRule: "not-us"},
+		{name: "falls back to the default", region: "us", plan: "free", want: 10, wantRule: ""},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			key := newKey()
+			ctx := context.Background()
+
+			if tt.region != "" {
+				ctx = region.WithValue(ctx, tt.region)
+			}
+
+			if tt.plan != "" {
+				ctx = plan.WithValue(ctx, tt.plan)
+			}
+
+			inspection := key.Inspect(ctx)
+			if inspection.Value != tt.want || inspection.Rule != tt.wantRule {
+				t.Errorf("Inspect() = %d (rule %q), want %d (rule %q)", inspection.Value, inspection.Rule, tt.want, tt.wantRule)
+			}
+
+			if inspection.Ok {
+				t.Error("Inspect().Ok = true, want false for computed value")
+			}
+		})
+	}
+
+	t.Run("context values win over rules", func(t *testing.T) {
+		t.Parallel()
+
+		key := newKey()
+		ctx := key.WithValue(region.WithValue(context.Background(), "eu"), 5)
+
+		if got := key.Get(ctx); got != 5 {
+			t.Errorf("Get() = %d, want 5", got)
+		}
+
+		if got := key.Inspect(ctx).Rule; got != "" {
+			t.Errorf("Inspect().Rule = %q, want empty", got)
+		}
+	})
+
+	t.Run("String reports the matched rule", func(t *testing.T) {
+		t.Parallel()
+
+		key := newKey()
+
+		want := "max-items: 50 (rule not-us)"
+		if got := key.Inspect(context.Background()).String(); got != want {
+			t.Errorf("Inspect().String() = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("rules win over the rollout", func(t *testing.T) {
+		t.Parallel()
+
+		userID := feature.NewNamed[string]("user-id")
+		key := feature.NewNamedBool("new-ui",
+			feature.WithRollout(100, userID),
+			feature.WithRules(feature.Rule[bool]{Name: "eu", When: feature.Equals(region, "eu"), Value: false}),
+		)
+
+		ctx := userID.WithValue(context.Background(), "user-1")
+		if !key.Enabled(ctx) {
+			t.Error("Enabled() = false, want rollout to apply")
+		}
+
+		if key.Enabled(region.WithValue(ctx, "eu")) {
+			t.Error("Enabled() = true, want rule to win over the rollout")
+		}
+	})
+
+	t.Run("nil condition always applies", func(t *testing.T) {
+		t.Parallel()
+
+		catchAll := feature.Rule[string]{Name: "catch-all", When: nil, Value: "hi"}
+		key := feature.NewNamed[string]("greeting", feature.WithRules(catchAll))
+
+		if got := key.Get(context.Background()); got != "hi" {
+			t.Errorf("Get() = %q, want %q", got, "hi")
+		}
+	})
+
+	t.Run("invalid rules panic", func(t *testing.T) {
+		t.Parallel()
+
+		tests := []struct {
+			name string
+			new  func()
+			want string
+		}{
+			{
+				name: "mismatched type",
+				new: func() {
+					_ = feature.NewNamed[int]("max-items", feature.WithRules(feature.Rule[string]{Name: "r", When: nil, Value: ""}))
+				},
+				want: "rule of type feature.Rule[string] is not applicable to key max-items of type int",
+			},
+			{
+				name: "missing name",
+				new: func() {
+					_ = feature.NewNamed[int]("max-items", feature.WithRules(feature.Rule[int]{Name: "", When: nil, Value: 1}))
+				},
+				want: "rule of key max-items must have a name",
+			},
+			{
+				name: "invalid value",
+				new: func() {
+					_ = feature.NewNamed[int]("max-items",
+						feature.WithValidator(nonNegative),
+						feature.WithRules(feature.Rule[int]{Name: "r", When: nil, Value: -1}),
+					)
+				},
+				want: "rule r: invalid value -1 for key max-items: must not be negative",
+			},
+		}
+
+		for _, tt := range tests {
+			tt := tt
+			t.Run(tt.name, func(t *testing.T) {
+				t.Parallel()
+
+				defer func() {
+					r := recover()
+					if r == nil {
+						t.Fatal("constructor did not panic")
+					}
+
+					checkContains(t, fmt.Sprint(r), tt.want)
+				}()
+
+				tt.new()
+			})
+		}
+	})
+}
+
+// TestParseRules tests loading rules from JSON documents.
+func TestParseRules(t *testing.T) {
+	t.Parallel()
+
+	t.Run("conditions refer to keys by name", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+		plan := feature.NewNamed[string]("plan", feature.WithRegistry(registry))
+		beta := feature.NewNamedBool("beta", feature.WithRegistry(registry))
+
+		rules, err := feature.ParseRules[bool](registry, []byte(`[
+			{"name": "eu-pro", "value": true,
+				"when": {"all": [{"key": "region", "equals": "eu"}, {"key": "plan", "equals": "pro"}]}},
+			{"name": "beta", "value": true,
+				"when": {"any": [{"key": "beta", "equals": true}, {"not": {"key": "region", "equals": "eu"}}]}},
+			{"name": "otherwise", "value": false}
+		]`))
+		if err != nil {
+			t.Fatalf("ParseRules() error = %v", err)
+		}
+
+		key := feature.NewNamedBool("new-ui", feature.WithRules(rules...))
+
+		tests := []struct {
+			ctx  context.Context
+			want string
+		}{
+			{ctx: plan.WithValue(region.WithValue(context.Background(), "eu"), "pro"), want: "new-ui: true (rule eu-pro)"},
+			{ctx: beta.WithEnabled(region.WithValue(context.Background(), "eu")), want: "new-ui: true (rule beta)"},
+			{ctx: region.WithValue(context.Background(), "us"), want: "new-ui: true (rule beta)"},
+			{ctx: region.WithValue(context.Background(), "eu"), want: "new-ui: false (rule otherwise)"},
+		}
+
+		for _, tt := range tests {
+			if got := key.Inspect(tt.ctx).String(); got != tt.want {
+				t.Errorf("Inspect().String() = %q, want %q", got, tt.want)
+			}
+		}
+	})
+
+	t.Run("errors are reported for each rule", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+		_, err := feature.ParseRules[bool](registry, []byte(`[
+			{"name": "unknown", "when": {"key": "missing", "equals": 1}, "value": true},
+			{"name": "mistyped", "when": {"key": "max-items", "equals": "many"}, "value": true},
+			{"name": "ambiguous", "when": {"key": "max-items", "equals": 1, "all": []}, "value": true},
+			{"name": "wrong-value", "value": "yes"},
+			{"value": true}
+		]`))
+
+		var unknownErr *feature.UnknownKeyError
+		if !errors.As(err, &unknownErr) || unknownErr.Name != "missing" {
+			t.Errorf("ParseRules() error = %v, want *feature.UnknownKeyError for missing", err)
+		}
+
+		var parseErr *feature.ParseError
+		if !errors.As(err, &parseErr) || parseErr.Key != "max-items" {
+			t.Errorf("ParseRules() error = %v, want *feature.ParseError for max-items", err)
+		}
+
+		if !errors.Is(err, feature.ErrMalformedRule) {
+			t.Errorf("ParseRules() error = %v, want %v", err, feature.ErrMalformedRule)
+		}
+
+		for _, want := range []string{"rule #2", "rule #3", "rule #4: malformed rule: missing name"} {
+			checkContains(t, fmt.Sprint(err), want)
+		}
+	})
+
+	t.Run("invalid JSON is reported", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := feature.ParseRules[bool](feature.NewRegistry(), []byte(`{}`))
+		if !errors.Is(err, feature.ErrMalformedRule) {
+			t.Errorf("ParseRules() error = %v, want %v", err, feature.ErrMalformedRule)
+		}
+	})
+}
+
+func ExampleWithRules() {
+	var (
+		Region = feature.NewNamed[string]("region")
+		Plan   = feature.NewNamed[string]("plan")
+		NewUI  = feature.NewNamedBool("new-ui", feature.WithRules(feature.Rule[bool]{
+			Name:  "eu-pro",
+			When:  feature.All(feature.Equals(Region, "eu"), feature.Equals(Plan, "pro")),
+			Value: true,
+		}))
+	)
+
+	ctx := Region.WithValue(context.Background(), "eu")
+	fmt.Println(NewUI.Inspect(ctx))
+
+	ctx = Plan.WithValue(ctx, "pro")
+	fmt.Println(NewUI.Inspect(ctx))
+
+	// Output:
+	// new-ui: <not set>
+	// new-ui: true (rule eu-pro)
+}
+
+func ExampleParseRules() {
+	registry := feature.NewRegistry()
+
+	var Region = feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+	rules, err := feature.ParseRules[int](registry, []byte(`[
+		{"name": "eu", "when": {"key": "region", "equals": "eu"}, "value": 50}
+	]`))
+	if err != nil {
+		panic(err)
+	}
+
+	var MaxItems = feature.NewNamed[int]("max-items", feature.WithDefault(100), feature.WithRules(rules...))
+
+	fmt.Println(MaxItems.Inspect(context.Background()))
+	fmt.Println(MaxItems.Inspect(Region.WithValue(context.Background(), "eu")))
+
+	// Output:
+	// max-items: 100 (default)
+	// max-items: 50 (rule eu)
+}
@@ -0,0 +1,123 @@
+package feature
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogGroupKey is the name of the group in which the handler created by NewLogHandler
+// appends the feature flags set in the context.
+const LogGroupKey = "features"
+
+// LogValue returns a group with the name, value, set flag and reason of the inspection,
+// plus the matched rule if any.
+// This implements slog.LogValuer.
+//
+// Example:
+//
+//	slog.InfoContext(ctx, "rendering", "max_items", MaxItems.Inspect(ctx))
+//	// Output: ... max_items.name=max-items max_items.value=100 max_items.set=false max_items.reason=default
+func (i Inspection[V]) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("name", i.Key.String()),
+		slog.Any("value", i.Value),
+		slog.Bool("set", i.Ok),
+		slog.String("reason", i.Reason.String()),
+	}
+
+	if i.Rule != "" {
+		attrs = append(attrs, slog.String("rule", i.Rule))
+	}
+
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue returns the same group as the embedded Inspection.LogValue().
+// This implements slog.LogValuer.
+func (i BoolInspection) LogValue() slog.Value {
+	return i.Inspection.LogValue()
+}
+
+// LogValue returns the same group as the underlying Inspection.
+// This implements slog.LogValuer.
+func (i AnyInspection) LogValue() slog.Value {
+	return i.inspection.LogValue()
+}
+
+// LogAttrs returns one attribute per key recorded in the Registry, in registration order,
+// keyed by the key name and valued by its inspection against the context.
+//
+// Example:
+//
+//	logger.LogAttrs(ctx, slog.LevelInfo, "request", registry.LogAttrs(ctx)...)
+func (r *Registry) LogAttrs(ctx context.Context) []slog.Attr {
+	inspections := r.Snapshot(ctx)
+	attrs := make([]slog.Attr, 0, len(inspections))
+
+	for _, inspection := range inspections {
+		attrs = append(attrs, slog.Any(inspection.Name(), inspection))
+	}
+
+	return attrs
+}
+
+// LogAttrs returns one attribute per key recorded in the DefaultRegistry.
+// It is equivalent to DefaultRegistry().LogAttrs(ctx).
+func LogAttrs(ctx context.Context) []slog.Attr {
+	return DefaultRegistry().LogAttrs(ctx)
+}
+
+// logHandler is the slog.Handler returned by NewLogHandler.
+type logHandler struct {
+	handler  slog.Handler
+	registry *Registry
+}
+
+// NewLogHandler returns a slog.Handler that appends the values of the keys recorded in the
+// Registry and set in the record's context to every record, as a group named LogGroupKey,
+// before passing it to handler. Records whose context has no such key set are passed unchanged.
+// Overrides, defaults and rules are not reflected, and hooks are not called.
+//
+// Example:
+//
+//	logger := slog.New(feature.NewLogHandler(slog.NewJSONHandler(os.Stderr, nil), registry))
+//	logger.InfoContext(ctx, "rendering")
+//	// Output: {"time":...,"level":"INFO","msg":"rendering","features":{"new-ui":true,"max-items":100}}
+func NewLogHandler(handler slog.Handler, registry *Registry) slog.Handler {
+	return &logHandler{handler: handler, registry: registry}
+}
+
+// Enabled implements slog.Handler.
+func (h *logHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.handler.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *logHandler) Handle(ctx context.Context, record slog.Record) error {
+	var attrs []any
+
+	// Only values set in the context are logged, so look them up without calling hooks,
+	// evaluating rules or consulting overrides.
+	for _, entry := range h.registry.snapshot() {
+		if value, ok := entry.Key.contextAny(ctx); ok {
+			attrs = append(attrs, slog.Any(entry.Name, value))
+		}
+	}
+
+	if len(attrs) > 0 {
+		record = record.Clone()
+		record.AddAttrs(slog.Group(LogGroupKey, attrs...))
+	}
+
+	return h.handler.Handle(ctx, record) //nolint:wrapcheck // errors of the wrapped handler are passed through
+}
+
+// WithAttrs implements slog.Handler.
+func (h *logHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &logHandler{handler: h.handler.WithAttrs(attrs), registry: h.registry}
+}
+
+// WithGroup implements slog.Handler.
+func (h *logHandler) WithGroup(name string) slog.Handler {
+	return &logHandler{handler: h.handler.WithGroup(name), registry: h.registry}
+}
@@ -0,0 +1,213 @@
+package feature_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// newTestLogger returns a logger writing records without timestamps to the buffer.
+func newTestLogger(buf *bytes.Buffer, wrap func(slog.Handler) slog.Handler) *slog.Logger {
+	handler := slog.Handler(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		AddSource: false,
+		Level:     nil,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}))
+
+	if wrap != nil {
+		handler = wrap(handler)
+	}
+
+	return slog.New(handler)
+}
+
+// TestInspectionLogValue tests logging inspections with log/slog.
+func TestInspectionLogValue(t *testing.T) {
+	t.Parallel()
+
+	region := feature.NewNamed[string]("region")
+	maxItems := feature.NewNamed[int]("max-items",
+		feature.WithDefault(100),
+		feature.WithRules(feature.Rule[int]{Name: "eu", When: feature.Equals(region, "eu"), Value: 50}),
+	)
+	newUI := feature.NewNamedBool("new-ui")
+
+	tests := []struct {
+		name string
+		attr slog.Attr
+		want string
+	}{
+		{
+			name: "default value",
+			attr: slog.Any("items", maxItems.Inspect(context.Background())),
+			want: "items.name=max-items items.value=100 items.set=false items.reason=default",
+		},
+		{
+			name: "rule match",
+			attr: slog.Any("items", maxItems.Inspect(region.WithValue(context.Background(), "eu"))),
+			want: "items.name=max-items items.value=50 items.set=false items.reason=rule-match items.rule=eu",
+		},
+		{
+			name: "bool inspection",
+			attr: slog.Any("ui", newUI.InspectBool(newUI.WithEnabled(context.Background()))),
+			want: "ui.name=new-ui ui.value=true ui.set=true ui.reason=context-override",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var buf bytes.Buffer
+
+			newTestLogger(&buf, nil).LogAttrs(context.Background(), slog.LevelInfo, "msg", tt.attr)
+
+			checkContains(t, buf.String(), tt.want)
+		})
+	}
+}
+
+// TestLogAttrs tests converting a snapshot into slog attributes.
+func TestLogAttrs(t *testing.T) {
+	t.Parallel()
+
+	registry := feature.NewRegistry()
+	newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+	_ = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+
+	attrs := registry.LogAttrs(newUI.WithEnabled(context.Background()))
+	if len(attrs) != 2 || attrs[0].Key != "new-ui" || attrs[1].Key != "max-items" {
+		t.Fatalf("LogAttrs() = %v, want attributes for new-ui and max-items", attrs)
+	}
+
+	var buf bytes.Buffer
+
+	newTestLogger(&buf, nil).LogAttrs(context.Background(), slog.LevelInfo, "msg", attrs...)
+
+	checkContains(t, buf.String(), "new-ui.value=true new-ui.set=true")
+	checkContains(t, buf.String(), "max-items.value=0 max-items.set=false max-items.reason=not-set")
+}
+
+// TestNewLogHandler tests appending the set feature flags to log records.
+func TestNewLogHandler(t *testing.T) {
+	t.Parallel()
+
+	registry := feature.NewRegistry()
+	newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+	maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry), feature.WithDefault(10))
+	_ = feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+	wrap := func(h slog.Handler) slog.Handler { return feature.NewLogHandler(h, registry) }
+
+	t.Run("set keys are appended", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		ctx := maxItems.WithValue(newUI.WithEnabled(context.Background()), 100)
+		newTestLogger(&buf, wrap).With("request", "r1").InfoContext(ctx, "rendering")
+
+		want := "level=INFO msg=rendering request=r1 features.new-ui=true features.max-items=100\n"
+		if got := buf.String(); got != want {
+			t.Errorf("log output = %q, want %q", got, want)
+		}
+	})
+
+	t.Run("records without set keys are unchanged", func(t *testing.T) {
+		t.Parallel()
+
+		var buf bytes.Buffer
+
+		newTestLogger(&buf, wrap).WithGroup("g").InfoContext(context.Background(), "rendering", "k", "v")
+
+		if got := buf.String(); strings.Contains(got, "features") || !strings.Contains(got, "g.k=v") {
+			t.Errorf("log output = %q, want no features", got)
+		}
+	})
+
+	t.Run("overrides and hooks are not involved", func(t *testing.T) {
+		t.Parallel()
+
+		var (
+			buf   bytes.Buffer
+			calls int
+		)
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		region := feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+		defer registry.AddHook(func(context.Context, feature.AnyInspection) { calls++ })()
+
+		restore := feature.Override(region, "eu")
+		defer restore()
+
+		logger := newTestLogger(&buf, func(h slog.Handler) slog.Handler { return feature.NewLogHandler(h, registry) })
+		logger.InfoContext(newUI.WithEnabled(context.Background()), "rendering")
+
+		want := "level=INFO msg=rendering features.new-ui=true\n"
+		if got := buf.String(); got != want {
+			t.Errorf("log output = %q, want %q", got, want)
+		}
+
+		if calls != 0 {
+			t.Errorf("hook called %d times, want 0", calls)
+		}
+	})
+
+	t.Run("levels are delegated", func(t *testing.T) {
+		t.Parallel()
+
+		handler := feature.NewLogHandler(slog.NewTextHandler(&bytes.Buffer{}, &slog.HandlerOptions{
+			AddSource:   false,
+			Level:       slog.LevelWarn,
+			ReplaceAttr: nil,
+		}), registry)
+
+		if handler.Enabled(context.Background(), slog.LevelInfo) {
+			t.Error("Enabled(Info) = true, want false")
+		}
+	})
+}
+
+func ExampleNewLogHandler() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+	)
+
+	logger := slog.New(feature.NewLogHandler(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{
+		AddSource: false,
+		Level:     nil,
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+
+			return a
+		},
+	}), registry))
+
+	ctx := context.Background()
+	ctx = EnableNewUI.WithEnabled(ctx)
+	ctx = MaxItems.WithValue(ctx, 100)
+
+	logger.InfoContext(ctx, "rendering")
+
+	// Output:
+	// {"level":"INFO","msg":"rendering","features":{"new-ui":true,"max-items":100}}
+}
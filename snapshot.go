@@ -0,0 +1,115 @@
+package feature
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+)
+
+// AnyInspection is a type-erased Inspection.
+//
+// It allows inspections of keys with different value types to be collected together,
+// for example in the result of Snapshot.
+type AnyInspection struct {
+	inspection erasedInspection
+}
+
+// erasedInspection is implemented by every Inspection[V].
+type erasedInspection interface {
+	fmt.Stringer
+	slog.LogValuer
+	IsSet() bool
+	anyKey() AnyKey
+	anyValue() any
+	anyReason() Reason
+	anyRule() string
+}
+
+// Key returns the inspected key.
+func (i AnyInspection) Key() AnyKey {
+	return i.inspection.anyKey()
+}
+
+// Expiry returns the expiry of the inspected key given with WithExpiry,
+// or the zero time if there is none.
+func (i AnyInspection) Expiry() time.Time {
+	expiry, _ := i.inspection.anyKey().metadata()
+
+	return expiry
+}
+
+// Owner returns the owner of the inspected key given with WithOwner, or empty if there is none.
+func (i AnyInspection) Owner() string {
+	_, owner := i.inspection.anyKey().metadata()
+
+	return owner
+}
+
+// Name returns the name of the inspected key.
+func (i AnyInspection) Name() string {
+	return i.inspection.anyKey().String()
+}
+
+// Type returns the value type V of the inspected key.
+func (i AnyInspection) Type() reflect.Type {
+	return i.inspection.anyKey().valueType()
+}
+
+// Value returns the value retrieved from the context.
+// If the key was not set, it returns the value computed by the rules or the rollout of the key
+// or the default value of the key, if any, or the zero value of the key's value type.
+func (i AnyInspection) Value() any {
+	return i.inspection.anyValue()
+}
+
+// IsSet returns true if the key was set in the context.
+func (i AnyInspection) IsSet() bool {
+	return i.inspection.IsSet()
+}
+
+// Reason returns why the value has its value.
+func (i AnyInspection) Reason() Reason {
+	return i.inspection.anyReason()
+}
+
+// Rule returns the name of the rule that computed the value if Reason is ReasonRuleMatch,
+// or empty otherwise.
+func (i AnyInspection) Rule() string {
+	return i.inspection.anyRule()
+}
+
+// String returns the same representation as the underlying Inspection,
+// e.g. "max-items: 100", "max-items: 100 (default)" or "max-items: <not set>".
+// This implements fmt.Stringer.
+func (i AnyInspection) String() string {
+	return i.inspection.String()
+}
+
+// Snapshot inspects every key recorded in the Registry against the context.
+//
+// The result contains one AnyInspection per registered key, in registration order,
+// regardless of whether the key is set in the context.
+func (r *Registry) Snapshot(ctx context.Context) []AnyInspection {
+	entries := r.snapshot()
+	inspections := make([]AnyInspection, 0, len(entries))
+
+	for _, entry := range entries {
+		inspections = append(inspections, entry.Key.inspectAny(ctx))
+	}
+
+	return inspections
+}
+
+// Snapshot inspects every key recorded in the DefaultRegistry against the context.
+// It is equivalent to DefaultRegistry().Snapshot(ctx).
+//
+// Example:
+//
+//	for _, inspection := range feature.Snapshot(ctx) {
+//	    log.Println(inspection) // Output: "max-items: 100", "new-ui: <not set>", ...
+//	}
+func Snapshot(ctx context.Context) []AnyInspection {
+	return DefaultRegistry().Snapshot(ctx)
+}
@@ -0,0 +1,122 @@
+package feature_test
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mpyw/feature"
+)
+
+// TestSnapshot tests enumerating registered keys against a context.
+func TestSnapshot(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty registry returns empty snapshot", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+
+		if got := registry.Snapshot(context.Background()); len(got) != 0 {
+			t.Errorf("Snapshot() = %v, want empty", got)
+		}
+	})
+
+	t.Run("includes set and unset keys in registration order", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		newUI := feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		maxItems := feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+		_ = feature.NewNamed[string]("region", feature.WithRegistry(registry))
+
+		ctx := context.Background()
+		ctx = newUI.WithDisabled(ctx)
+		ctx = maxItems.WithValue(ctx, 100)
+
+		snapshot := registry.Snapshot(ctx)
+		if len(snapshot) != 3 {
+			t.Fatalf("len(Snapshot()) = %d, want 3", len(snapshot))
+		}
+
+		tests := []struct {
+			name    string
+			typ     reflect.Type
+			value   any
+			isSet   bool
+			display string
+		}{
+			{"new-ui", reflect.TypeOf(false), false, true, "new-ui: false"},
+			{"max-items", reflect.TypeOf(0), 100, true, "max-items: 100"},
+			{"region", reflect.TypeOf(""), "", false, "region: <not set>"},
+		}
+
+		for idx, want := range tests {
+			got := snapshot[idx]
+
+			if got.Name() != want.name {
+				t.Errorf("snapshot[%d].Name() = %q, want %q", idx, got.Name(), want.name)
+			}
+
+			if got.Type() != want.typ {
+				t.Errorf("snapshot[%d].Type() = %v, want %v", idx, got.Type(), want.typ)
+			}
+
+			if got.Value() != want.value {
+				t.Errorf("snapshot[%d].Value() = %#v, want %#v", idx, got.Value(), want.value)
+			}
+
+			if got.IsSet() != want.isSet {
+				t.Errorf("snapshot[%d].IsSet() = %v, want %v", idx, got.IsSet(), want.isSet)
+			}
+
+			if got.String() != want.display {
+				t.Errorf("snapshot[%d].String() = %q, want %q", idx, got.String(), want.display)
+			}
+		}
+	})
+
+	t.Run("package-level Snapshot uses DefaultRegistry", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("snapshot-test-default", feature.WithRegistry(feature.DefaultRegistry()))
+		ctx := key.WithValue(context.Background(), 7)
+
+		for _, inspection := range feature.Snapshot(ctx) {
+			// Earlier runs with -count register keys of the same name.
+			if inspection.Key() == key {
+				if inspection.Value() != 7 {
+					t.Errorf("Value() = %v, want 7", inspection.Value())
+				}
+
+				return
+			}
+		}
+
+		t.Error("Snapshot() does not contain key registered in DefaultRegistry")
+	})
+}
+
+func ExampleRegistry_Snapshot() {
+	registry := feature.NewRegistry()
+
+	var (
+		EnableNewUI = feature.NewNamedBool("new-ui", feature.WithRegistry(registry))
+		MaxItems    = feature.NewNamed[int]("max-items", feature.WithRegistry(registry))
+		_           = feature.NewNamed[string]("region", feature.WithRegistry(registry))
+	)
+
+	ctx := context.Background()
+	ctx = EnableNewUI.WithEnabled(ctx)
+	ctx = MaxItems.WithValue(ctx, 100)
+
+	for _, inspection := range registry.Snapshot(ctx) {
+		fmt.Printf("%s (%s, set=%v)\n", inspection, inspection.Type(), inspection.IsSet())
+	}
+
+	// Output:
+	// new-ui: true (bool, set=true)
+	// max-items: 100 (int, set=true)
+	// region: <not set> (string, set=false)
+}
@@ -0,0 +1,84 @@
+package feature
+
+import (
+	"fmt"
+)
+
+// ValidationError is returned when a value is rejected by a validator given with WithValidator.
+type ValidationError struct {
+	// Key is the name of the key the value was rejected for.
+	Key string
+	// Value is the rejected value.
+	Value any
+	// Err is the error returned by the validator.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid value %v for key %s: %v", e.Value, e.Key, e.Err)
+}
+
+// Unwrap returns the error returned by the validator.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// WithValidator returns an option that adds a validator for the values of the key.
+//
+// Validators run in the order they are given whenever a value is associated with the key:
+// WithValue panics with a *ValidationError if a validator rejects the value, while TryWithValue
+// returns the error. Values read by loaders such as LoadEnv, LoadFile and BindFlags are
+// validated as well, and the default value given with WithDefault is validated when the key
+// is created.
+//
+// The validator must accept exactly the value type of the key; otherwise creating the key panics.
+//
+// Example:
+//
+//	var MaxRetries = feature.NewNamed[int]("max-retries", feature.WithValidator(func(v int) error {
+//	    if v < 0 {
+//	        return errors.New("must not be negative")
+//	    }
+//	    return nil
+//	}))
+//
+//	ctx, err := MaxRetries.TryWithValue(ctx, -5)
+//	fmt.Println(err) // Output: invalid value -5 for key max-retries: must not be negative
+func WithValidator[V any](validator func(value V) error) Option {
+	return func(o *options) {
+		o.validators = append(o.validators, validator)
+	}
+}
+
+// validatorsFrom converts the validators collected in options to the value type of the key.
+func validatorsFrom[V any](name string, validators []any) []func(V) error {
+	if len(validators) == 0 {
+		return nil
+	}
+
+	typed := make([]func(V) error, 0, len(validators))
+
+	for _, validator := range validators {
+		fn, ok := validator.(func(V) error)
+		if !ok {
+			panic(fmt.Sprintf("validator of type %T is not applicable to key %s of type %s",
+				validator, name, typeOf[V]()))
+		}
+
+		typed = append(typed, fn)
+	}
+
+	return typed
+}
+
+// validate runs the validators of the key against the value.
+func (k key[V]) validate(value V) error {
+	for _, validator := range k.validators {
+		if err := validator(value); err != nil {
+			return &ValidationError{Key: k.name, Value: value, Err: err}
+		}
+	}
+
+	return nil
+}
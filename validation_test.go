@@ -0,0 +1,247 @@
+package feature_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mpyw/feature"
+)
+
+var errNegative = errors.New("must not be negative")
+
+func nonNegative(v int) error {
+	if v < 0 {
+		return errNegative
+	}
+
+	return nil
+}
+
+// TestWithValidator tests value validation hooks.
+func TestWithValidator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("TryWithValue accepts valid values", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-retries", feature.WithValidator(nonNegative))
+
+		ctx, err := key.TryWithValue(context.Background(), 5)
+		if err != nil {
+			t.Fatalf("TryWithValue() error = %v", err)
+		}
+
+		if got := key.Get(ctx); got != 5 {
+			t.Errorf("Get() = %d, want 5", got)
+		}
+	})
+
+	t.Run("TryWithValue rejects invalid values", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-retries", feature.WithValidator(nonNegative))
+		base := context.Background()
+
+		ctx, err := key.TryWithValue(base, -5)
+		if !errors.Is(err, errNegative) {
+			t.Fatalf("TryWithValue() error = %v, want %v", err, errNegative)
+		}
+
+		var validationErr *feature.ValidationError
+		if !errors.As(err, &validationErr) {
+			t.Fatalf("TryWithValue() error = %#v, want *feature.ValidationError", err)
+		}
+
+		if validationErr.Key != "max-retries" || validationErr.Value != -5 {
+			t.Errorf("ValidationError = %+v, want key max-retries and value -5", validationErr)
+		}
+
+		want := "invalid value -5 for key max-retries: must not be negative"
+		if err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+
+		if ctx != base {
+			t.Error("TryWithValue() did not return the original context on error")
+		}
+	})
+
+	t.Run("WithValue panics with a message naming the key", func(t *testing.T) {
+		t.Parallel()
+
+		key := feature.NewNamed[int]("max-retries", feature.WithValidator(nonNegative))
+
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("WithValue() did not panic for invalid value")
+			}
+
+			err, ok := r.(error)
+			if !ok || !errors.Is(err, errNegative) {
+				t.Errorf("panic value = %#v, want *feature.ValidationError", r)
+			}
+
+			checkContains(t, fmt.Sprint(r), "max-retries")
+		}()
+
+		_ = key.WithValue(context.Background(), -5)
+	})
+
+	t.Run("BoolKey helpers are validated", func(t *testing.T) {
+		t.Parallel()
+
+		errOff := errors.New("cannot be disabled")
+		flag := feature.NewNamedBool("kill-switch", feature.WithValidator(func(v bool) error {
+			if !v {
+				return errOff
+			}
+
+			return nil
+		}))
+
+		ctx := flag.WithEnabled(context.Background())
+		if !flag.Enabled(ctx) {
+			t.Error("Enabled() = false, want true")
+		}
+
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("WithDisabled() did not panic for invalid value")
+			}
+		}()
+
+		_ = flag.WithDisabled(ctx)
+	})
+
+	t.Run("validators run in order and stop at the first error", func(t *testing.T) {
+		t.Parallel()
+
+		var calls []string
+
+		key := feature.New[int](
+			feature.WithValidator(func(int) error {
+				calls = append(calls, "first")
+
+				return errNegative
+			}),
+			feature.WithValidator(func(int) error {
+				calls = append(calls, "second")
+
+				return nil
+			}),
+		)
+
+		if _, err := key.TryWithValue(context.Background(), 1); err == nil {
+			t.Error("TryWithValue() error = nil, want error")
+		}
+
+		if strings.Join(calls, ",") != "first" {
+			t.Errorf("validators called = %v, want [first]", calls)
+		}
+	})
+
+	t.Run("default value is validated at construction", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("NewNamed() did not panic for invalid default")
+			}
+
+			checkContains(t, fmt.Sprint(r), "invalid value -1 for key max-retries")
+		}()
+
+		_ = feature.NewNamed[int]("max-retries", feature.WithDefault(-1), feature.WithValidator(nonNegative))
+	})
+
+	t.Run("mismatched validator type panics", func(t *testing.T) {
+		t.Parallel()
+
+		defer func() {
+			r := recover()
+			if r == nil {
+				t.Fatal("NewNamed() did not panic for mismatched validator type")
+			}
+
+			checkContains(t, fmt.Sprint(r), "validator of type func(int) error is not applicable to key timeout")
+		}()
+
+		_ = feature.NewNamed[time.Duration]("timeout", feature.WithValidator(nonNegative))
+	})
+
+	t.Run("loaders run validators", func(t *testing.T) {
+		t.Parallel()
+
+		registry := feature.NewRegistry()
+		key := feature.NewNamed[int]("max-retries", feature.WithRegistry(registry), feature.WithValidator(nonNegative))
+
+		path := filepath.Join(t.TempDir(), "features.json")
+		if err := os.WriteFile(path, []byte(`{"max-retries": -5}`), 0o600); err != nil {
+			t.Fatalf("WriteFile() error = %v", err)
+		}
+
+		ctx, err := registry.LoadFile(context.Background(), path)
+		if !errors.Is(err, errNegative) {
+			t.Errorf("LoadFile() error = %v, want %v", err, errNegative)
+		}
+
+		if key.IsSet(ctx) {
+			t.Error("IsSet() = true, want invalid value to be skipped")
+		}
+
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(&bytes.Buffer{})
+		_ = feature.BindFlags(fs, registry)
+
+		err = fs.Parse([]string{"-feature.max-retries=-5"})
+		if err == nil || !strings.Contains(err.Error(), "must not be negative") {
+			t.Errorf("Parse() error = %v, want validation error", err)
+		}
+	})
+}
+
+func ExampleWithValidator() {
+	var MaxRetries = feature.NewNamed[int]("max-retries", feature.WithValidator(func(v int) error {
+		if v < 0 {
+			return errors.New("must not be negative")
+		}
+
+		return nil
+	}))
+
+	_, err := MaxRetries.TryWithValue(context.Background(), -5)
+	fmt.Println(err)
+
+	// Output:
+	// invalid value -5 for key max-retries: must not be negative
+}
+
+func ExampleKey_TryWithValue() {
+	var MaxItems = feature.NewNamed[int]("max-items", feature.WithValidator(func(v int) error {
+		if v > 1000 {
+			return errors.New("must be at most 1000")
+		}
+
+		return nil
+	}))
+
+	ctx, err := MaxItems.TryWithValue(context.Background(), 100)
+	fmt.Println(MaxItems.Inspect(ctx), err)
+
+	_, err = MaxItems.TryWithValue(ctx, 5000)
+	fmt.Println(err)
+
+	// Output:
+	// max-items: 100 <nil>
+	// invalid value 5000 for key max-items: must be at most 1000
+}